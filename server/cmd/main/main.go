@@ -5,6 +5,7 @@ import (
 
 	"github.com/rs/zerolog/log"
 	"github.com/william-joh/quizzer/server/internal/api"
+	"github.com/william-joh/quizzer/server/internal/blob"
 	"github.com/william-joh/quizzer/server/internal/execution"
 	"github.com/william-joh/quizzer/server/internal/postgres"
 )
@@ -18,11 +19,26 @@ func main() {
 	}
 	defer db.Close()
 
-	executioner := execution.NewInMemory(db)
+	codes, err := execution.CodeConfigFromEnv()
+	if err != nil {
+		log.Panic().Err(err).Msg("invalid game code config")
+	}
+
+	executioner := execution.NewInMemory(db, codes)
 	executioner.Run()
 	defer executioner.Stop()
 
-	api := api.NewAPI(db, executioner)
+	media, err := blob.LocalConfigFromEnv()
+	if err != nil {
+		log.Panic().Err(err).Msg("invalid media config")
+	}
+
+	blobs, err := blob.NewLocal(media)
+	if err != nil {
+		log.Panic().Err(err).Msg("failed to setup media store")
+	}
+
+	api := api.NewAPI(db, executioner, blobs)
 	if err := api.Run(); err != nil {
 		log.Panic().Err(err).Msg("failed to run api")
 	}
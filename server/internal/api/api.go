@@ -2,11 +2,13 @@ package api
 
 import (
 	"net/http"
+	"net/url"
 	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/rs/cors"
 	"github.com/rs/zerolog/log"
+	"github.com/william-joh/quizzer/server/internal/blob"
 	"github.com/william-joh/quizzer/server/internal/execution"
 	"github.com/william-joh/quizzer/server/internal/postgres"
 )
@@ -17,12 +19,21 @@ type API interface {
 }
 
 type server struct {
-	db         postgres.Database
-	exectioner execution.Service
+	db          postgres.Database
+	exectioner  execution.Service
+	blobs       blob.BlobStore
+	gameLimiter *gameLimiter
+	guestTokens *guestTokens
 }
 
-func NewAPI(db postgres.Database, executioner execution.Service) API {
-	return &server{db: db, exectioner: executioner}
+func NewAPI(db postgres.Database, executioner execution.Service, blobs blob.BlobStore) API {
+	return &server{
+		db:          db,
+		exectioner:  executioner,
+		blobs:       blobs,
+		gameLimiter: newGameLimiter(),
+		guestTokens: newGuestTokens(),
+	}
 }
 
 func (s *server) Handler() http.Handler {
@@ -30,30 +41,58 @@ func (s *server) Handler() http.Handler {
 	r.Use(loggerMiddleware)
 
 	r.Handle("/game/{code}", s.wsHandler()).Methods(http.MethodGet)
+	r.Handle("/game/{code}/events", s.eventsHandler()).Methods(http.MethodGet)
+	r.Handle("/game/{code}/actions", s.actionsHandler()).Methods(http.MethodPost)
 
 	r.Handle("/auth", s.authHandler()).Methods(http.MethodPost)
 	r.Handle("/users", s.createUserHandler()).Methods(http.MethodPost)
+	r.Handle("/guests", s.createGuestHandler()).Methods(http.MethodPost)
 	r.HandleFunc("/healthz", healthzHandler)
 
+	// Media is shown to participants, who may not be logged in.
+	r.Handle("/media/{key}", s.getMediaHandler()).Methods(http.MethodGet, http.MethodHead)
+
+	r.Handle("/assignments/{id}", s.getAssignmentHandler()).Methods(http.MethodGet)
+	r.Handle("/assignments/{id}/attempt", s.startAttemptHandler()).Methods(http.MethodPost)
+	r.Handle("/assignments/{id}/attempt", s.getAttemptHandler()).Methods(http.MethodGet)
+	r.Handle("/assignments/{id}/attempt/answers", s.answerAssignmentHandler()).Methods(http.MethodPost)
+
 	authorized := r.NewRoute().Subrouter()
 	authorized.Use(s.authMiddleware)
 
 	authorized.Handle("/current-user", s.getUserHandler()).Methods(http.MethodGet)
 	authorized.Handle("/users/{id}", s.deleteUserHandler()).Methods(http.MethodDelete)
 
+	authorized.Handle("/media", s.uploadMediaHandler()).Methods(http.MethodPost)
+
 	authorized.Handle("/quizzes", s.createQuizHandler()).Methods(http.MethodPost)
 	authorized.Handle("/quizzes/{id}/start", s.startQuizHandler()).Methods(http.MethodPost)
+	authorized.Handle("/quizzes/{id}/assignments", s.createAssignmentHandler()).Methods(http.MethodPost)
 	authorized.Handle("/quizzes", s.listQuizzesHandler()).Methods(http.MethodGet)
 	authorized.Handle("/quizzes/{id}", s.getQuizHandler()).Methods(http.MethodGet)
 	authorized.Handle("/quizzes/{id}", s.deleteQuizHandler()).Methods(http.MethodDelete)
 
+	authorized.Handle("/games/live", s.listLiveGamesHandler()).Methods(http.MethodGet)
+	authorized.Handle("/games/live/{code}", s.getLiveGameHandler()).Methods(http.MethodGet)
+	authorized.Handle("/games/live/{code}/{action}", s.controlLiveGameHandler()).Methods(http.MethodPost)
+	authorized.Handle("/games/live/{code}/questions/{questionId}", s.editLiveQuestionHandler()).Methods(http.MethodPut)
+
+	authorized.Handle("/games", s.listGamesHandler()).Methods(http.MethodGet)
+	authorized.Handle("/games/{id}/results", s.getGameResultsHandler()).Methods(http.MethodGet)
+
+	authorized.Handle("/scheduled-games", s.createScheduledGameHandler()).Methods(http.MethodPost)
+	authorized.Handle("/scheduled-games", s.listScheduledGamesHandler()).Methods(http.MethodGet)
+	authorized.Handle("/scheduled-games/{id}", s.rescheduleGameHandler()).Methods(http.MethodPut)
+	authorized.Handle("/scheduled-games/{id}", s.cancelScheduledGameHandler()).Methods(http.MethodDelete)
+
 	return r
 }
 
 func (s *server) Run() error {
 	c := cors.New(cors.Options{
 		AllowedOrigins:   []string{"http://localhost:5173", "http://127.0.0.1:5173"}, // All origins
-		AllowedMethods:   []string{"GET", "POST", "DELETE", "HEAD"},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "HEAD"},
+		AllowedHeaders:   []string{"Accept", "Content-Type", "X-Requested-With", guestTokenHeader},
 		AllowCredentials: true,
 	})
 
@@ -74,9 +113,34 @@ func healthzHandler(w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte("OK"))
 }
 
+// secretParams are query parameters that are left out of the logs. Websockets
+// and event streams cannot send headers from browsers, so they are given guest
+// tokens and join passwords in the query.
+var secretParams = []string{"guestToken", "password"}
+
 func loggerMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		log.Debug().Str("method", r.Method).Str("url", r.URL.String()).Msg("request")
+		log.Debug().Str("method", r.Method).Str("url", redactedURL(r.URL)).Msg("request")
 		next.ServeHTTP(w, r)
 	})
 }
+
+// redactedURL returns the URL with the values of secret query parameters
+// replaced.
+func redactedURL(u *url.URL) string {
+	query := u.Query()
+	redacted := false
+	for _, param := range secretParams {
+		if query.Has(param) {
+			query.Set(param, "REDACTED")
+			redacted = true
+		}
+	}
+	if !redacted {
+		return u.String()
+	}
+
+	c := *u
+	c.RawQuery = query.Encode()
+	return c.String()
+}
@@ -0,0 +1,577 @@
+package api
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog/log"
+	"github.com/william-joh/quizzer/server/internal/execution"
+	"github.com/william-joh/quizzer/server/internal/postgres"
+	"github.com/william-joh/quizzer/server/internal/quizzer"
+)
+
+var (
+	errAssignmentNotFound = errors.New("assignment not found")
+	errAssignmentClosed   = errors.New("assignment is not open")
+	errNoAttempt          = errors.New("assignment has not been started")
+	errAttemptStarted     = errors.New("assignment has already been started")
+	errNotCurrentQuestion = errors.New("question is not the current question")
+	errTimeUp             = errors.New("time is up for the question")
+	errUnknownOption      = errors.New("answer option does not exist")
+)
+
+// assignmentErrorStatus returns the HTTP status for an error from working on
+// an assignment.
+func assignmentErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, errAssignmentNotFound), errors.Is(err, errNoAttempt):
+		return http.StatusNotFound
+	case errors.Is(err, errUnknownOption):
+		return http.StatusBadRequest
+	case errors.Is(err, errAssignmentClosed):
+		return http.StatusForbidden
+	case errors.Is(err, errAttemptStarted), errors.Is(err, errNotCurrentQuestion), errors.Is(err, errTimeUp):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func (s *server) createAssignmentHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		quizID := mux.Vars(r)["id"]
+		userID := r.Context().Value(userIDKey).(string)
+
+		req := struct {
+			OpensAt  time.Time          `json:"opensAt"`
+			ClosesAt time.Time          `json:"closesAt"`
+			Settings execution.Settings `json:"settings"`
+		}{
+			OpensAt:  time.Now(),
+			Settings: execution.DefaultSettings(),
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			toJSONError(w, fmt.Errorf("failed to decode request body: %w", err), http.StatusBadRequest)
+			return
+		}
+		if err := req.Settings.Validate(); err != nil {
+			toJSONError(w, fmt.Errorf("invalid settings: %w", err), http.StatusBadRequest)
+			return
+		}
+		if !req.ClosesAt.After(req.OpensAt) || !req.ClosesAt.After(time.Now()) {
+			toJSONError(w, errors.New("the assignment must close after it opens, and in the future"), http.StatusBadRequest)
+			return
+		}
+
+		// Everyone works through the questions on their own, so there is no
+		// one to give a join password to.
+		req.Settings.JoinPassword = ""
+		settings, err := json.Marshal(req.Settings)
+		if err != nil {
+			toJSONError(w, fmt.Errorf("failed to encode settings: %w", err), http.StatusInternalServerError)
+			return
+		}
+
+		game := quizzer.Game{
+			ID:       uuid.New().String(),
+			QuizID:   quizID,
+			HostID:   userID,
+			Mode:     quizzer.GameModeAssignment,
+			Settings: settings,
+			OpensAt:  req.OpensAt,
+			ClosesAt: req.ClosesAt,
+		}
+
+		quiz, err := s.db.Do(r.Context()).GetQuiz(r.Context(), quizID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			toJSONError(w, errors.New("quiz not found"), http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			toJSONError(w, fmt.Errorf("failed to get quiz: %w", err), http.StatusInternalServerError)
+			return
+		}
+		if quiz.CreatedBy != userID {
+			toJSONError(w, errors.New("access denied"), http.StatusForbidden)
+			return
+		}
+
+		if err := s.db.Do(r.Context()).CreateGame(r.Context(), game); err != nil {
+			toJSONError(w, fmt.Errorf("failed to create assignment: %w", err), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		if err := json.NewEncoder(w).Encode(game); err != nil {
+			log.Error().Err(err).Msg("failed to encode response")
+		}
+	})
+}
+
+// attempt is the work of a single participant on an assignment.
+type attempt struct {
+	game        quizzer.Game
+	settings    execution.Settings
+	questions   []quizzer.Question
+	participant quizzer.GameParticipant
+	// answers holds the answers of the participant by question ID, including
+	// the questions that have been shown but not answered yet.
+	answers map[string]quizzer.GameAnswer
+}
+
+// loadAssignment loads an assignment and its questions, and the attempt of the
+// participant on it if they have started one.
+func loadAssignment(ctx context.Context, s postgres.Session, gameID string, identity execution.Identity) (attempt, bool, error) {
+	game, err := s.GetGame(ctx, gameID)
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && game.Mode != quizzer.GameModeAssignment) {
+		return attempt{}, false, errAssignmentNotFound
+	}
+	if err != nil {
+		return attempt{}, false, fmt.Errorf("get game: %w", err)
+	}
+
+	a := attempt{game: game, settings: execution.DefaultSettings(), answers: map[string]quizzer.GameAnswer{}}
+	if err := json.Unmarshal(game.Settings, &a.settings); err != nil {
+		return attempt{}, false, fmt.Errorf("decode settings: %w", err)
+	}
+
+	a.questions, err = s.ListQuestions(ctx, game.QuizID)
+	if err != nil {
+		return attempt{}, false, fmt.Errorf("list questions: %w", err)
+	}
+	slices.SortFunc(a.questions, func(a, b quizzer.Question) int {
+		return cmp.Compare(a.Index, b.Index)
+	})
+	a.questions = execution.ShuffleFor(a.settings, game.ID, identity.ID, a.questions)
+
+	a.participant, err = s.GetGameParticipant(ctx, gameID, identity.ID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return a, false, nil
+	}
+	if err != nil {
+		return attempt{}, false, fmt.Errorf("get game participant: %w", err)
+	}
+
+	answers, err := s.ListGameAnswers(ctx, a.participant.ID)
+	if err != nil {
+		return attempt{}, false, fmt.Errorf("list game answers: %w", err)
+	}
+	for _, answer := range answers {
+		a.answers[answer.QuestionID] = answer
+	}
+
+	return a, true, nil
+}
+
+// current returns the first question the participant has not answered yet.
+func (a *attempt) current() (quizzer.Question, bool) {
+	for _, q := range a.questions {
+		if a.answers[q.ID].AnsweredAt == nil {
+			return q, true
+		}
+	}
+	return quizzer.Question{}, false
+}
+
+// deadline returns when time is up for the question, if it has a timer and has
+// been shown.
+func (a *attempt) deadline(q quizzer.Question) (time.Time, bool) {
+	shownAt := a.answers[q.ID].ShownAt
+	if !a.settings.TimerEnabled || q.TimeLimitSeconds == 0 || shownAt == nil {
+		return time.Time{}, false
+	}
+	return shownAt.Add(time.Duration(q.TimeLimitSeconds) * time.Second), true
+}
+
+// advance moves the attempt on to the next question the participant should
+// answer. Questions whose time is up are left unanswered, and the attempt is
+// finished once all questions are answered or the assignment has closed.
+func (a *attempt) advance(ctx context.Context, s postgres.Session, now time.Time) error {
+	for a.participant.FinishedAt == nil {
+		q, ok := a.current()
+		if !ok || !a.game.IsOpen(now) {
+			a.participant.FinishedAt = &now
+			if err := s.UpdateGameParticipant(ctx, a.participant); err != nil {
+				return fmt.Errorf("update game participant: %w", err)
+			}
+			return nil
+		}
+
+		answer := a.answers[q.ID]
+		if answer.ShownAt == nil {
+			answer.ParticipantID = a.participant.ID
+			answer.QuestionID = q.ID
+			answer.ShownAt = &now
+			return a.save(ctx, s, answer)
+		}
+
+		deadline, ok := a.deadline(q)
+		if !ok || now.Before(deadline) {
+			return nil
+		}
+
+		answer.AnsweredAt = &deadline
+		if err := a.save(ctx, s, answer); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// answer answers the current question with the answer option.
+func (a *attempt) answer(ctx context.Context, s postgres.Session, questionID, optionID string, now time.Time) error {
+	q, ok := a.current()
+	if !ok || q.ID != questionID || a.participant.FinishedAt != nil {
+		return errNotCurrentQuestion
+	}
+
+	if _, ok := q.Option(optionID); !ok {
+		return fmt.Errorf("%w: %s", errUnknownOption, optionID)
+	}
+
+	var timeLeft time.Duration
+	if deadline, ok := a.deadline(q); ok {
+		if !now.Before(deadline) {
+			return errTimeUp
+		}
+		timeLeft = deadline.Sub(now)
+	}
+
+	answer := a.answers[q.ID]
+	answer.OptionID = &optionID
+	answer.IsCorrect = q.IsCorrect(optionID)
+	answer.Score = execution.Score(a.settings, q, optionID, timeLeft)
+	answer.AnsweredAt = &now
+	if err := a.save(ctx, s, answer); err != nil {
+		return err
+	}
+
+	a.participant.Score += answer.Score
+	if err := s.UpdateGameParticipant(ctx, a.participant); err != nil {
+		return fmt.Errorf("update game participant: %w", err)
+	}
+
+	return nil
+}
+
+func (a *attempt) save(ctx context.Context, s postgres.Session, answer quizzer.GameAnswer) error {
+	if err := s.SaveGameAnswer(ctx, answer); err != nil {
+		return fmt.Errorf("save game answer: %w", err)
+	}
+	a.answers[answer.QuestionID] = answer
+	return nil
+}
+
+type assignmentOption struct {
+	ID       string  `json:"id"`
+	Text     string  `json:"text"`
+	ImageURL *string `json:"imageUrl,omitempty"`
+	AudioURL *string `json:"audioUrl,omitempty"`
+}
+
+type assignmentResult struct {
+	QuestionID     string             `json:"questionId"`
+	Question       string             `json:"question"`
+	OptionID       *string            `json:"optionId"`
+	IsCorrect      bool               `json:"isCorrect"`
+	Score          int                `json:"score"`
+	CorrectOptions []assignmentOption `json:"correctOptions"`
+}
+
+// state returns what the participant should see, in the same shape as the
+// payloads of live games.
+func (a *attempt) state(now time.Time) interface{} {
+	if q, ok := a.current(); ok && a.participant.FinishedAt == nil {
+		var timeLeft uint64
+		if deadline, ok := a.deadline(q); ok {
+			timeLeft = uint64(max(0, deadline.Sub(now).Round(time.Second)/time.Second))
+		}
+
+		return struct {
+			Phase          execution.Phase    `json:"phase"`
+			QuestionID     string             `json:"questionId"`
+			Question       string             `json:"question"`
+			QuestionNumber int                `json:"questionNumber"`
+			TotalQuestions int                `json:"totalQuestions"`
+			Options        []assignmentOption `json:"options"`
+			TimeLimit      uint64             `json:"timeLimit"`
+			// There is no host screen in an assignment, so the media of
+			// the question is shown to the participant.
+			ImageURL *string `json:"imageUrl,omitempty"`
+			AudioURL *string `json:"audioUrl,omitempty"`
+		}{
+			Phase:          execution.PhaseQuestion,
+			QuestionID:     q.ID,
+			Question:       q.Question,
+			QuestionNumber: slices.IndexFunc(a.questions, func(o quizzer.Question) bool { return o.ID == q.ID }) + 1,
+			TotalQuestions: len(a.questions),
+			Options:        toAssignmentOptions(q.Options),
+			TimeLimit:      timeLeft,
+			ImageURL:       q.ImageURL,
+			AudioURL:       q.AudioURL,
+		}
+	}
+
+	// The correct answers are only shown once the assignment has closed, so
+	// that they cannot be passed on to those still working on it.
+	var results []assignmentResult
+	if a.settings.ShowCorrectAnswers && !now.Before(a.game.ClosesAt) {
+		results = []assignmentResult{}
+		for _, q := range a.questions {
+			answer := a.answers[q.ID]
+			results = append(results, assignmentResult{
+				QuestionID:     q.ID,
+				Question:       q.Question,
+				OptionID:       answer.OptionID,
+				IsCorrect:      answer.IsCorrect,
+				Score:          answer.Score,
+				CorrectOptions: toAssignmentOptions(q.CorrectOptions()),
+			})
+		}
+	}
+
+	var resultsAt *time.Time
+	if a.settings.ShowCorrectAnswers && results == nil {
+		resultsAt = &a.game.ClosesAt
+	}
+
+	return struct {
+		Phase          execution.Phase    `json:"phase"`
+		TotalScore     int                `json:"totalScore"`
+		TotalQuestions int                `json:"totalQuestions"`
+		Results        []assignmentResult `json:"results,omitempty"`
+		// ResultsAt is when the results can be reviewed, if not yet.
+		ResultsAt *time.Time `json:"resultsAt,omitempty"`
+	}{
+		Phase:          execution.PhaseResults,
+		TotalScore:     a.participant.Score,
+		TotalQuestions: len(a.questions),
+		Results:        results,
+		ResultsAt:      resultsAt,
+	}
+}
+
+func toAssignmentOptions(options []quizzer.AnswerOption) []assignmentOption {
+	result := []assignmentOption{}
+	for _, o := range options {
+		result = append(result, assignmentOption{ID: o.ID, Text: o.Text, ImageURL: o.ImageURL, AudioURL: o.AudioURL})
+	}
+	return result
+}
+
+func (s *server) getAssignmentHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gameID := mux.Vars(r)["id"]
+		identity, err := s.identify(r)
+		if err != nil {
+			toJSONError(w, fmt.Errorf("identify participant: %w", err), http.StatusUnauthorized)
+			return
+		}
+
+		var resp struct {
+			quizzer.Game
+			QuizTitle      string      `json:"quizTitle"`
+			TotalQuestions int         `json:"totalQuestions"`
+			Started        bool        `json:"started"`
+			State          interface{} `json:"state,omitempty"`
+		}
+		err = s.db.InTx(r.Context(), func(s postgres.Session) error {
+			a, started, err := loadAssignment(r.Context(), s, gameID, identity)
+			if err != nil {
+				return err
+			}
+
+			quiz, err := s.GetQuiz(r.Context(), a.game.QuizID)
+			if err != nil {
+				return fmt.Errorf("get quiz: %w", err)
+			}
+
+			resp.Game = a.game
+			resp.QuizTitle = quiz.Title
+			resp.TotalQuestions = len(a.questions)
+			resp.Started = started
+			if started {
+				if err := a.advance(r.Context(), s, time.Now()); err != nil {
+					return err
+				}
+				resp.State = a.state(time.Now())
+			}
+			return nil
+		})
+		if err != nil {
+			toJSONError(w, err, assignmentErrorStatus(err))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			log.Error().Err(err).Msg("failed to encode response")
+		}
+	})
+}
+
+func (s *server) startAttemptHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gameID := mux.Vars(r)["id"]
+		identity, err := s.identify(r)
+		if err != nil {
+			toJSONError(w, fmt.Errorf("identify participant: %w", err), http.StatusUnauthorized)
+			return
+		}
+
+		var state interface{}
+		err = s.db.InTx(r.Context(), func(s postgres.Session) error {
+			a, started, err := loadAssignment(r.Context(), s, gameID, identity)
+			if err != nil {
+				return err
+			}
+			if started {
+				return errAttemptStarted
+			}
+
+			now := time.Now()
+			if !a.game.IsOpen(now) {
+				return errAssignmentClosed
+			}
+			if identity.IsGuest && a.settings.RequireAccount {
+				return fmt.Errorf("%w: an account is required", errAssignmentClosed)
+			}
+
+			a.participant = quizzer.GameParticipant{
+				ID:        uuid.New().String(),
+				GameID:    a.game.ID,
+				UserID:    identity.ID,
+				Name:      identity.Name,
+				IsGuest:   identity.IsGuest,
+				StartedAt: now,
+			}
+			if err := s.CreateGameParticipant(r.Context(), a.participant); err != nil {
+				return fmt.Errorf("create game participant: %w", err)
+			}
+
+			if err := a.advance(r.Context(), s, now); err != nil {
+				return err
+			}
+			state = a.state(now)
+			return nil
+		})
+		if err != nil {
+			toJSONError(w, err, assignmentErrorStatus(err))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		if err := json.NewEncoder(w).Encode(state); err != nil {
+			log.Error().Err(err).Msg("failed to encode response")
+		}
+	})
+}
+
+func (s *server) getAttemptHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gameID := mux.Vars(r)["id"]
+		identity, err := s.identify(r)
+		if err != nil {
+			toJSONError(w, fmt.Errorf("identify participant: %w", err), http.StatusUnauthorized)
+			return
+		}
+
+		var state interface{}
+		err = s.db.InTx(r.Context(), func(s postgres.Session) error {
+			a, started, err := loadAssignment(r.Context(), s, gameID, identity)
+			if err != nil {
+				return err
+			}
+			if !started {
+				return errNoAttempt
+			}
+
+			now := time.Now()
+			if err := a.advance(r.Context(), s, now); err != nil {
+				return err
+			}
+			state = a.state(now)
+			return nil
+		})
+		if err != nil {
+			toJSONError(w, err, assignmentErrorStatus(err))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(state); err != nil {
+			log.Error().Err(err).Msg("failed to encode response")
+		}
+	})
+}
+
+func (s *server) answerAssignmentHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gameID := mux.Vars(r)["id"]
+		identity, err := s.identify(r)
+		if err != nil {
+			toJSONError(w, fmt.Errorf("identify participant: %w", err), http.StatusUnauthorized)
+			return
+		}
+
+		var req struct {
+			QuestionID string `json:"questionId"`
+			OptionID   string `json:"optionId"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			toJSONError(w, fmt.Errorf("failed to decode request body: %w", err), http.StatusBadRequest)
+			return
+		}
+
+		var state interface{}
+		err = s.db.InTx(r.Context(), func(s postgres.Session) error {
+			a, started, err := loadAssignment(r.Context(), s, gameID, identity)
+			if err != nil {
+				return err
+			}
+			if !started {
+				return errNoAttempt
+			}
+
+			// Time may have run out on the question being answered, or the
+			// assignment may have closed, since the participant last asked.
+			now := time.Now()
+			if err := a.advance(r.Context(), s, now); err != nil {
+				return err
+			}
+			if !a.game.IsOpen(now) {
+				return errAssignmentClosed
+			}
+			if err := a.answer(r.Context(), s, req.QuestionID, req.OptionID, now); err != nil {
+				return err
+			}
+
+			if err := a.advance(r.Context(), s, now); err != nil {
+				return err
+			}
+			state = a.state(now)
+			return nil
+		})
+		if err != nil {
+			toJSONError(w, err, assignmentErrorStatus(err))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(state); err != nil {
+			log.Error().Err(err).Msg("failed to encode response")
+		}
+	})
+}
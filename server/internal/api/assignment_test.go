@@ -0,0 +1,62 @@
+package api
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/william-joh/quizzer/server/internal/execution"
+	"github.com/william-joh/quizzer/server/internal/quizzer"
+)
+
+func TestAttemptState(t *testing.T) {
+	closesAt := time.Now().Add(time.Hour)
+	now := closesAt.Add(-time.Minute)
+	answeredAt := now.Add(-time.Minute)
+	wrong := "o2"
+	question := quizzer.Question{ID: "q1", Question: "q1", Options: []quizzer.AnswerOption{
+		{ID: "o1", Text: "a", IsCorrect: true},
+		{ID: "o2", Text: "b"},
+	}}
+
+	tests := []struct {
+		name               string
+		showCorrectAnswers bool
+		at                 time.Time
+		results            bool
+	}{
+		{name: "hidden while open", showCorrectAnswers: true, at: now, results: false},
+		{name: "shown once closed", showCorrectAnswers: true, at: closesAt, results: true},
+		{name: "never shown if not asked for", at: closesAt, results: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := execution.DefaultSettings()
+			settings.ShowCorrectAnswers = tt.showCorrectAnswers
+			a := attempt{
+				game:        quizzer.Game{ClosesAt: closesAt},
+				settings:    settings,
+				questions:   []quizzer.Question{question},
+				participant: quizzer.GameParticipant{FinishedAt: &answeredAt},
+				answers: map[string]quizzer.GameAnswer{
+					"q1": {QuestionID: "q1", OptionID: &wrong, AnsweredAt: &answeredAt},
+				},
+			}
+
+			data, err := json.Marshal(a.state(tt.at))
+			require.NoError(t, err)
+			var state map[string]any
+			require.NoError(t, json.Unmarshal(data, &state))
+
+			require.Equal(t, string(execution.PhaseResults), state["phase"])
+			if tt.results {
+				require.Len(t, state["results"], 1)
+				require.NotContains(t, state, "resultsAt")
+			} else {
+				require.NotContains(t, state, "results")
+				require.Equal(t, tt.showCorrectAnswers, state["resultsAt"] != nil)
+			}
+		})
+	}
+}
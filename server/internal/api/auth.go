@@ -3,11 +3,13 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 
 	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
+	"github.com/william-joh/quizzer/server/internal/execution"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -71,6 +73,36 @@ func (s *server) authMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// identify returns who is behind the request: the logged in user if the
+// request has a valid session cookie, otherwise the guest of the guest token in
+// the guestToken query parameter.
+func (s *server) identify(r *http.Request) (execution.Identity, error) {
+	if sessionID, err := r.Cookie("quizzer_session_id"); err == nil {
+		userID, err := s.db.Do(r.Context()).GetAuthSession(r.Context(), sessionID.Value)
+		if err == nil {
+			user, err := s.db.Do(r.Context()).GetUser(r.Context(), userID)
+			if err != nil {
+				return execution.Identity{}, fmt.Errorf("get user: %w", err)
+			}
+			return execution.Identity{ID: user.ID, Name: user.Username}, nil
+		}
+	}
+
+	token := r.Header.Get(guestTokenHeader)
+	if token == "" {
+		token = r.URL.Query().Get("guestToken")
+	}
+	if token == "" {
+		return execution.Identity{}, errors.New("not logged in and no guest token given")
+	}
+
+	gst, err := s.guestTokens.verify(token)
+	if err != nil {
+		return execution.Identity{}, err
+	}
+	return execution.Identity{ID: gst.ID, Name: gst.Name, IsGuest: true}, nil
+}
+
 type errorResponse struct {
 	Error string `json:"error"`
 }
@@ -0,0 +1,118 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog/log"
+	"github.com/william-joh/quizzer/server/internal/postgres"
+	"github.com/william-joh/quizzer/server/internal/quizzer"
+)
+
+var errGameNotFound = errors.New("game not found")
+
+// listGamesHandler lists the finished live games and the assignments of the
+// user.
+func (s *server) listGamesHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID := r.Context().Value(userIDKey).(string)
+
+		games, err := s.db.Do(r.Context()).ListGames(r.Context(), userID)
+		if err != nil {
+			toJSONError(w, fmt.Errorf("failed to list games: %w", err), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(games); err != nil {
+			log.Error().Err(err).Msg("failed to encode response")
+		}
+	})
+}
+
+// getGameResultsHandler returns the answers of every participant of a game, for
+// the host to review.
+func (s *server) getGameResultsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gameID := mux.Vars(r)["id"]
+		userID := r.Context().Value(userIDKey).(string)
+
+		type participantResults struct {
+			quizzer.GameParticipant
+			Answers []quizzer.GameAnswer `json:"answers"`
+		}
+		var resp struct {
+			Game         quizzer.Game         `json:"game"`
+			Quiz         quizzer.Quiz         `json:"quiz"`
+			Questions    []quizzer.Question   `json:"questions"`
+			Participants []participantResults `json:"participants"`
+		}
+
+		err := s.db.InTx(r.Context(), func(s postgres.Session) error {
+			game, err := s.GetGame(r.Context(), gameID)
+			if errors.Is(err, pgx.ErrNoRows) || (err == nil && game.HostID != userID) {
+				return errGameNotFound
+			}
+			if err != nil {
+				return fmt.Errorf("get game: %w", err)
+			}
+			resp.Game = game
+
+			resp.Quiz, err = s.GetQuiz(r.Context(), game.QuizID)
+			if err != nil {
+				return fmt.Errorf("get quiz: %w", err)
+			}
+
+			resp.Questions, err = s.ListQuestions(r.Context(), game.QuizID)
+			if err != nil {
+				return fmt.Errorf("list questions: %w", err)
+			}
+
+			participants, err := s.ListGameParticipants(r.Context(), game.ID)
+			if err != nil {
+				return fmt.Errorf("list game participants: %w", err)
+			}
+
+			ids := []string{}
+			for _, p := range participants {
+				ids = append(ids, p.ID)
+			}
+			answers, err := s.ListGameAnswers(r.Context(), ids...)
+			if err != nil {
+				return fmt.Errorf("list game answers: %w", err)
+			}
+
+			byParticipant := map[string][]quizzer.GameAnswer{}
+			for _, a := range answers {
+				byParticipant[a.ParticipantID] = append(byParticipant[a.ParticipantID], a)
+			}
+
+			resp.Participants = []participantResults{}
+			for _, p := range participants {
+				resp.Participants = append(resp.Participants, participantResults{
+					GameParticipant: p,
+					Answers:         append([]quizzer.GameAnswer{}, byParticipant[p.ID]...),
+				})
+			}
+
+			return nil
+		})
+		if errors.Is(err, errGameNotFound) {
+			toJSONError(w, err, http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			toJSONError(w, err, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			log.Error().Err(err).Msg("failed to encode response")
+		}
+	})
+}
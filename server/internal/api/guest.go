@@ -0,0 +1,143 @@
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	guestSecretEnv = "QUIZZER_GUEST_SECRET"
+	guestTokenTTL  = 24 * time.Hour
+	// guestTokenHeader carries the guest token of requests that can send
+	// headers. Others give it in the guestToken query parameter.
+	guestTokenHeader = "X-Guest-Token"
+	maxGuestName     = 32
+)
+
+type guest struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// guestTokens issues and verifies the signed tokens that identify guests, so
+// that participants without an account cannot pick the identity of others.
+type guestTokens struct {
+	secret []byte
+}
+
+// newGuestTokens signs tokens with the secret in QUIZZER_GUEST_SECRET. If it is
+// not set a random secret is used, which invalidates all guest tokens when the
+// server restarts.
+func newGuestTokens() *guestTokens {
+	secret := []byte(os.Getenv(guestSecretEnv))
+	if len(secret) == 0 {
+		log.Warn().Msgf("%s not set, using a random secret for guest tokens", guestSecretEnv)
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			log.Panic().Err(err).Msg("failed to generate guest token secret")
+		}
+	}
+
+	return &guestTokens{secret: secret}
+}
+
+func (g *guestTokens) issue(name string) (string, guest, error) {
+	gst := guest{
+		ID:        uuid.New().String(),
+		Name:      name,
+		ExpiresAt: time.Now().Add(guestTokenTTL).Unix(),
+	}
+
+	payload, err := json.Marshal(gst)
+	if err != nil {
+		return "", guest{}, err
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + g.sign(encoded), gst, nil
+}
+
+func (g *guestTokens) verify(token string) (guest, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return guest{}, errors.New("malformed guest token")
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(g.sign(encoded))) {
+		return guest{}, errors.New("invalid guest token signature")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return guest{}, fmt.Errorf("decode guest token: %w", err)
+	}
+
+	var gst guest
+	if err := json.Unmarshal(payload, &gst); err != nil {
+		return guest{}, fmt.Errorf("decode guest token: %w", err)
+	}
+
+	if time.Now().Unix() > gst.ExpiresAt {
+		return guest{}, errors.New("guest token expired")
+	}
+
+	return gst, nil
+}
+
+func (g *guestTokens) sign(encoded string) string {
+	mac := hmac.New(sha256.New, g.secret)
+	mac.Write([]byte(encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func (s *server) createGuestHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Name string `json:"name"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			toJSONError(w, fmt.Errorf("decode request body: %w", err), http.StatusBadRequest)
+			return
+		}
+
+		name := strings.TrimSpace(req.Name)
+		if name == "" || len([]rune(name)) > maxGuestName {
+			toJSONError(w, fmt.Errorf("name must be between 1 and %d characters", maxGuestName), http.StatusBadRequest)
+			return
+		}
+
+		token, gst, err := s.guestTokens.issue(name)
+		if err != nil {
+			toJSONError(w, fmt.Errorf("issue guest token: %w", err), http.StatusInternalServerError)
+			return
+		}
+
+		resp := struct {
+			ID    string `json:"id"`
+			Name  string `json:"name"`
+			Token string `json:"token"`
+		}{
+			ID:    gst.ID,
+			Name:  gst.Name,
+			Token: token,
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			log.Error().Err(err).Msg("failed to encode response")
+		}
+	})
+}
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGuestTokens(t *testing.T) {
+	tokens := &guestTokens{secret: []byte("secret")}
+
+	token, issued, err := tokens.issue("guest")
+	require.NoError(t, err)
+	require.NotEmpty(t, issued.ID)
+	require.Equal(t, "guest", issued.Name)
+
+	other, _, err := (&guestTokens{secret: []byte("other")}).issue("guest")
+	require.NoError(t, err)
+
+	encode := func(g guest) string {
+		payload, err := json.Marshal(g)
+		require.NoError(t, err)
+		return base64.RawURLEncoding.EncodeToString(payload)
+	}
+	// signed returns a token for the guest, signed with the secret.
+	signed := func(g guest) string {
+		encoded := encode(g)
+		return encoded + "." + tokens.sign(encoded)
+	}
+	encoded, signature, _ := strings.Cut(token, ".")
+
+	tests := []struct {
+		name  string
+		token string
+		want  guest
+		err   string
+	}{
+		{
+			name:  "issued token",
+			token: token,
+			want:  issued,
+		},
+		{
+			name:  "signed by another secret",
+			token: other,
+			err:   "invalid guest token signature",
+		},
+		{
+			name:  "changed payload",
+			token: encode(guest{ID: issued.ID, Name: "admin", ExpiresAt: issued.ExpiresAt}) + "." + signature,
+			err:   "invalid guest token signature",
+		},
+		{
+			name:  "expired",
+			token: signed(guest{ID: issued.ID, Name: issued.Name, ExpiresAt: time.Now().Add(-time.Minute).Unix()}),
+			err:   "guest token expired",
+		},
+		{
+			name:  "without signature",
+			token: encoded,
+			err:   "malformed guest token",
+		},
+		{
+			name:  "empty",
+			token: "",
+			err:   "malformed guest token",
+		},
+		{
+			name:  "signed garbage",
+			token: "!!!." + tokens.sign("!!!"),
+			err:   "decode guest token",
+		},
+		{
+			name:  "signed non-json",
+			token: "bm90IGpzb24." + tokens.sign("bm90IGpzb24"),
+			err:   "decode guest token",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tokens.verify(tt.token)
+			if tt.err != "" {
+				require.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
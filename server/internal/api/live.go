@@ -0,0 +1,180 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/rs/zerolog/log"
+	"github.com/william-joh/quizzer/server/internal/execution"
+	"github.com/william-joh/quizzer/server/internal/quizzer"
+)
+
+func (s *server) listLiveGamesHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID := r.Context().Value(userIDKey).(string)
+
+		executions, err := s.exectioner.ListExecutions(r.Context(), userID)
+		if err != nil {
+			toJSONError(w, fmt.Errorf("list executions: %w", err), http.StatusInternalServerError)
+			return
+		}
+
+		statuses := []execution.Status{}
+		for _, e := range executions {
+			status, err := e.Status(userID)
+			if errors.Is(err, execution.ErrForbidden) {
+				// The user lost their co-host rights since the executions
+				// were listed.
+				continue
+			}
+			if err != nil {
+				toJSONError(w, fmt.Errorf("get status of %s: %w", e.Code, err), http.StatusInternalServerError)
+				return
+			}
+			statuses = append(statuses, status)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(statuses); err != nil {
+			log.Error().Err(err).Msg("failed to encode response")
+		}
+	})
+}
+
+func (s *server) getLiveGameHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID := r.Context().Value(userIDKey).(string)
+
+		e, ok := s.lookupLiveGame(w, r, userID)
+		if !ok {
+			return
+		}
+
+		status, err := e.Status(userID)
+		if err != nil {
+			toJSONError(w, err, controlErrorStatus(err))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(status); err != nil {
+			log.Error().Err(err).Msg("failed to encode response")
+		}
+	})
+}
+
+// lookupLiveGame returns the execution with the code in the URL, if the user
+// is its host or a co-host. Games the user does not control are answered like
+// games that do not exist and count as failed lookups, so that the codes of
+// running games cannot be found by trying them.
+func (s *server) lookupLiveGame(w http.ResponseWriter, r *http.Request, userID string) (*execution.Execution, bool) {
+	e, ip, ok := s.lookupGame(w, r)
+	if !ok {
+		return nil, false
+	}
+
+	if !e.CanControl(userID) {
+		s.gameLimiter.fail(ip)
+		toJSONError(w, execution.ErrNotFound, http.StatusNotFound)
+		return nil, false
+	}
+
+	return e, true
+}
+
+// controlLiveGameHandler does the action in the URL, e.g. next or end, to a
+// running game.
+func (s *server) controlLiveGameHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		userID := r.Context().Value(userIDKey).(string)
+
+		e, ok := s.lookupLiveGame(w, r, userID)
+		if !ok {
+			return
+		}
+
+		if err := e.Control(userID, execution.Action(vars["action"])); err != nil {
+			toJSONError(w, err, controlErrorStatus(err))
+			return
+		}
+
+		status, err := e.Status(userID)
+		if err != nil {
+			toJSONError(w, err, controlErrorStatus(err))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(status); err != nil {
+			log.Error().Err(err).Msg("failed to encode response")
+		}
+	})
+}
+
+// editLiveQuestionHandler fixes a question of a running game, and also saves
+// the fix to the quiz if asked to.
+func (s *server) editLiveQuestionHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		userID := r.Context().Value(userIDKey).(string)
+
+		var edit execution.QuestionEdit
+		if err := json.NewDecoder(r.Body).Decode(&edit); err != nil {
+			toJSONError(w, fmt.Errorf("failed to decode request body: %w", err), http.StatusBadRequest)
+			return
+		}
+
+		e, ok := s.lookupLiveGame(w, r, userID)
+		if !ok {
+			return
+		}
+
+		// Only the owner of the quiz may change it, co-hosts and hosts the
+		// game was handed over to can only fix the running game.
+		if edit.Save && e.Quiz.CreatedBy != userID {
+			toJSONError(w, errors.New("only the owner of the quiz can save to it"), http.StatusForbidden)
+			return
+		}
+
+		var saveErr error
+		save := func(q quizzer.Question) error {
+			saveErr = s.db.Do(r.Context()).UpdateQuestion(r.Context(), q)
+			return saveErr
+		}
+
+		question, err := e.EditQuestion(userID, vars["questionId"], edit, save)
+		if saveErr != nil {
+			toJSONError(w, fmt.Errorf("failed to save question: %w", saveErr), http.StatusInternalServerError)
+			return
+		}
+		if err != nil {
+			toJSONError(w, err, controlErrorStatus(err))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(question); err != nil {
+			log.Error().Err(err).Msg("failed to encode response")
+		}
+	})
+}
+
+// controlErrorStatus returns the HTTP status for an error from controlling an
+// execution. Errors that are not about who or what is asked for mean that the
+// action is not possible in the current phase.
+func controlErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, execution.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, execution.ErrUnknownAction), errors.Is(err, execution.ErrQuestionNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, execution.ErrInvalidEdit):
+		return http.StatusBadRequest
+	default:
+		return http.StatusConflict
+	}
+}
@@ -0,0 +1,175 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/william-joh/quizzer/server/internal/execution"
+	"github.com/william-joh/quizzer/server/internal/mocks"
+	"github.com/william-joh/quizzer/server/internal/postgres"
+	"github.com/william-joh/quizzer/server/internal/quizzer"
+)
+
+// testQuestion is the question of the quiz in test games.
+var testQuestion = quizzer.Question{ID: "q1", QuizID: "quiz-id", Question: "q1", TimeLimitSeconds: 20, Options: []quizzer.AnswerOption{
+	{ID: "o1", QuestionID: "q1", Text: "a", IsCorrect: true},
+	{ID: "o2", QuestionID: "q1", Text: "b", Index: 1},
+}}
+
+// newTestServer returns a server running a game of a quiz owned by host-id,
+// with the session the database gives out, and the code of the game.
+func newTestServer(t *testing.T, settings execution.Settings) (*server, *mocks.Session, string) {
+	session := &mocks.Session{}
+	session.On("GetQuiz", mock.Anything, "quiz-id").Return(quizzer.Quiz{ID: "quiz-id", Title: "quiz", CreatedBy: "host-id"}, nil)
+	session.On("ListQuestions", mock.Anything, "quiz-id").Return([]quizzer.Question{testQuestion}, nil)
+	session.On("ListSlides", mock.Anything, "quiz-id").Return([]quizzer.Slide{}, nil)
+	session.On("ListSections", mock.Anything, "quiz-id").Return([]quizzer.Section{}, nil)
+	session.On("GetUser", mock.Anything, "host-id").Return(quizzer.User{ID: "host-id", Username: "host"}, nil)
+
+	db := &mocks.Database{}
+	db.On("Do", mock.Anything).Return(session)
+	db.On("InTx", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		require.NoError(t, args.Get(1).(func(postgres.Session) error)(session))
+	})
+
+	executions := execution.NewInMemory(db, execution.DefaultCodeConfig())
+	code, err := executions.CreateExecution(context.Background(), "quiz-id", "host-id", settings)
+	require.NoError(t, err)
+
+	return NewAPI(db, executions, nil).(*server), session, code
+}
+
+// serveAs serves the request to the handler as the user, with the variables
+// of the route.
+func serveAs(h http.Handler, userID, method, body string, vars map[string]string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, "/", strings.NewReader(body))
+	r.RemoteAddr = "1.2.3.4:1234"
+	r = mux.SetURLVars(r.WithContext(context.WithValue(r.Context(), userIDKey, userID)), vars)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func responseError(t *testing.T, w *httptest.ResponseRecorder) string {
+	var resp errorResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	return resp.Error
+}
+
+func TestGetLiveGame(t *testing.T) {
+	s, _, code := newTestServer(t, execution.DefaultSettings())
+
+	tests := []struct {
+		name   string
+		userID string
+		code   string
+		status int
+	}{
+		{name: "host", userID: "host-id", code: code, status: http.StatusOK},
+		{name: "unknown code", userID: "host-id", code: "unknown", status: http.StatusNotFound},
+		{name: "not a host", userID: "other-id", code: code, status: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s.gameLimiter = newGameLimiter()
+			w := serveAs(s.getLiveGameHandler(), tt.userID, http.MethodGet, "", map[string]string{"code": tt.code})
+			require.Equal(t, tt.status, w.Code)
+
+			if tt.status == http.StatusOK {
+				var status execution.Status
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&status))
+				require.Equal(t, code, status.Code)
+				require.Equal(t, execution.PhaseLobby, status.Phase)
+				return
+			}
+
+			// Games the user does not control cannot be told apart from
+			// games that do not exist, and count as failed lookups.
+			require.Equal(t, execution.ErrNotFound.Error(), responseError(t, w))
+			ok, _ := s.gameLimiter.allow("1.2.3.4")
+			require.False(t, ok)
+		})
+	}
+}
+
+func TestControlLiveGame(t *testing.T) {
+	tests := []struct {
+		name    string
+		userID  string
+		actions []string
+		status  int
+		phase   execution.Phase
+	}{
+		{name: "start", userID: "host-id", actions: []string{"start"}, status: http.StatusOK, phase: execution.PhaseQuestion},
+		{name: "pause", userID: "host-id", actions: []string{"start", "pause"}, status: http.StatusOK, phase: execution.PhasePaused},
+		{name: "finish", userID: "host-id", actions: []string{"start", "finish"}, status: http.StatusOK, phase: execution.PhaseResults},
+		{name: "unknown action", userID: "host-id", actions: []string{"bogus"}, status: http.StatusNotFound},
+		{name: "not possible in the phase", userID: "host-id", actions: []string{"resume"}, status: http.StatusConflict},
+		{name: "not a host", userID: "other-id", actions: []string{"start"}, status: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _, code := newTestServer(t, execution.DefaultSettings())
+
+			var w *httptest.ResponseRecorder
+			for _, action := range tt.actions {
+				w = serveAs(s.controlLiveGameHandler(), tt.userID, http.MethodPost, "", map[string]string{"code": code, "action": action})
+			}
+			require.Equal(t, tt.status, w.Code)
+			if tt.status != http.StatusOK {
+				return
+			}
+
+			var status execution.Status
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&status))
+			require.Equal(t, tt.phase, status.Phase)
+		})
+	}
+}
+
+func TestEditLiveQuestion(t *testing.T) {
+	edit := `{"question": "fixed", "options": [{"id": "o1", "text": "a"}, {"id": "o2", "text": "b", "isCorrect": true}]}`
+	saved := `{"question": "fixed", "options": [{"id": "o1", "text": "a"}, {"id": "o2", "text": "b", "isCorrect": true}], "save": true}`
+
+	tests := []struct {
+		name       string
+		userID     string
+		questionID string
+		body       string
+		saveErr    error
+		status     int
+	}{
+		{name: "fix", userID: "host-id", questionID: "q1", body: edit, status: http.StatusOK},
+		{name: "fix and save", userID: "host-id", questionID: "q1", body: saved, status: http.StatusOK},
+		{name: "save fails", userID: "host-id", questionID: "q1", body: saved, saveErr: errors.New("db down"), status: http.StatusInternalServerError},
+		{name: "unknown question", userID: "host-id", questionID: "q2", body: edit, status: http.StatusNotFound},
+		{name: "invalid edit", userID: "host-id", questionID: "q1", body: `{"question": "fixed", "options": [{"id": "o1", "text": "a"}]}`, status: http.StatusBadRequest},
+		{name: "not a host", userID: "other-id", questionID: "q1", body: edit, status: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, session, code := newTestServer(t, execution.DefaultSettings())
+			session.On("UpdateQuestion", mock.Anything, mock.Anything).Return(tt.saveErr)
+
+			w := serveAs(s.editLiveQuestionHandler(), tt.userID, http.MethodPut, tt.body, map[string]string{"code": code, "questionId": tt.questionID})
+			require.Equal(t, tt.status, w.Code)
+			if tt.status != http.StatusOK {
+				return
+			}
+
+			var q quizzer.Question
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&q))
+			require.Equal(t, "fixed", q.Question)
+			require.True(t, q.IsCorrect("o2"))
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/rs/zerolog/log"
+	"github.com/william-joh/quizzer/server/internal/blob"
+)
+
+// multipartOverhead is how much larger than the file an upload request can
+// be, for the multipart boundaries and headers around it.
+const multipartOverhead = 1 << 20
+
+// media is an uploaded file, as it is referred to from questions and answer
+// options.
+type media struct {
+	blob.Blob
+	URL          string `json:"url"`
+	ThumbnailURL string `json:"thumbnailUrl,omitempty"`
+}
+
+func mediaURL(key string) string {
+	return "/media/" + key
+}
+
+func (s *server) uploadMediaHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Requests larger than the store accepts are cut off before they are
+		// read.
+		r.Body = http.MaxBytesReader(w, r.Body, s.blobs.MaxSize()+multipartOverhead)
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				toJSONError(w, fmt.Errorf("upload too large"), http.StatusRequestEntityTooLarge)
+				return
+			}
+			toJSONError(w, fmt.Errorf("failed to read file: %w", err), http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+
+		b, err := s.blobs.Put(r.Context(), file)
+		switch {
+		case errors.Is(err, blob.ErrTooLarge):
+			toJSONError(w, err, http.StatusRequestEntityTooLarge)
+			return
+		case errors.Is(err, blob.ErrUnsupportedType):
+			toJSONError(w, err, http.StatusUnsupportedMediaType)
+			return
+		case err != nil:
+			toJSONError(w, fmt.Errorf("failed to store file: %w", err), http.StatusInternalServerError)
+			return
+		}
+
+		resp := media{Blob: b, URL: mediaURL(b.Key)}
+		if b.Thumbnail != "" {
+			resp.ThumbnailURL = mediaURL(b.Thumbnail)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			log.Error().Err(err).Msg("failed to encode response")
+			return
+		}
+	})
+}
+
+func (s *server) getMediaHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := mux.Vars(r)["key"]
+
+		f, b, err := s.blobs.Open(r.Context(), key)
+		if errors.Is(err, blob.ErrNotFound) {
+			toJSONError(w, err, http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			toJSONError(w, fmt.Errorf("failed to open file: %w", err), http.StatusInternalServerError)
+			return
+		}
+		defer f.Close()
+
+		// Blobs are named by their content, so they never change and can
+		// be cached for as long as clients want.
+		w.Header().Set("Content-Type", b.ContentType)
+		w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+		w.Header().Set("ETag", `"`+b.Key+`"`)
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		http.ServeContent(w, r, b.Key, time.Time{}, f)
+	})
+}
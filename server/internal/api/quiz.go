@@ -2,12 +2,15 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"github.com/rs/zerolog/log"
+	"github.com/william-joh/quizzer/server/internal/execution"
 	"github.com/william-joh/quizzer/server/internal/postgres"
 	"github.com/william-joh/quizzer/server/internal/quizzer"
 )
@@ -17,12 +20,54 @@ func (s *server) createQuizHandler() http.Handler {
 		var quiz struct {
 			Title     string             `json:"title"`
 			Questions []quizzer.Question `json:"questions"`
+			Slides    []quizzer.Slide    `json:"slides"`
+			// Sections have an ID chosen by the client, which the questions
+			// refer to as their section ID.
+			Sections []quizzer.Section `json:"sections"`
 		}
 		if err := json.NewDecoder(r.Body).Decode(&quiz); err != nil {
 			toJSONError(w, fmt.Errorf("failed to decode request body: %w", err), http.StatusBadRequest)
 			return
 		}
 
+		// Questions and slides share their ordering, so each index can only be
+		// used once.
+		indexes := map[int]bool{}
+		for i, q := range quiz.Questions {
+			if err := q.Validate(); err != nil {
+				toJSONError(w, fmt.Errorf("invalid question %d: %w", i+1, err), http.StatusBadRequest)
+				return
+			}
+			if indexes[q.Index] {
+				toJSONError(w, fmt.Errorf("invalid question %d: index %d is already used", i+1, q.Index), http.StatusBadRequest)
+				return
+			}
+			indexes[q.Index] = true
+		}
+
+		for i, sl := range quiz.Slides {
+			if err := sl.Validate(); err != nil {
+				toJSONError(w, fmt.Errorf("invalid slide %d: %w", i+1, err), http.StatusBadRequest)
+				return
+			}
+			if indexes[sl.Index] {
+				toJSONError(w, fmt.Errorf("invalid slide %d: index %d is already used", i+1, sl.Index), http.StatusBadRequest)
+				return
+			}
+			indexes[sl.Index] = true
+		}
+
+		for i, sec := range quiz.Sections {
+			if err := sec.Validate(); err != nil {
+				toJSONError(w, fmt.Errorf("invalid section %d: %w", i+1, err), http.StatusBadRequest)
+				return
+			}
+		}
+		if err := quizzer.ValidateSections(quiz.Sections, quiz.Questions); err != nil {
+			toJSONError(w, fmt.Errorf("invalid sections: %w", err), http.StatusBadRequest)
+			return
+		}
+
 		userID := r.Context().Value(userIDKey).(string)
 		quizID := uuid.New().String()
 
@@ -31,14 +76,41 @@ func (s *server) createQuizHandler() http.Handler {
 				return err
 			}
 
+			sectionIDs := map[string]string{}
+			for _, sec := range quiz.Sections {
+				sectionIDs[sec.ID] = uuid.New().String()
+				sec.ID = sectionIDs[sec.ID]
+				sec.QuizID = quizID
+				if err := s.CreateSection(r.Context(), sec); err != nil {
+					return err
+				}
+			}
+
 			for _, q := range quiz.Questions {
 				q.ID = uuid.New().String()
 				q.QuizID = quizID
+				if q.SectionID != nil {
+					sectionID := sectionIDs[*q.SectionID]
+					q.SectionID = &sectionID
+				}
+				for i := range q.Options {
+					q.Options[i].ID = uuid.New().String()
+					q.Options[i].QuestionID = q.ID
+					q.Options[i].Index = i
+				}
 				if err := s.CreateQuestion(r.Context(), q); err != nil {
 					return err
 				}
 			}
 
+			for _, sl := range quiz.Slides {
+				sl.ID = uuid.New().String()
+				sl.QuizID = quizID
+				if err := s.CreateSlide(r.Context(), sl); err != nil {
+					return err
+				}
+			}
+
 			return nil
 		})
 		if err != nil {
@@ -67,6 +139,8 @@ func (s *server) getQuizHandler() http.Handler {
 		var quiz struct {
 			Quiz      quizzer.Quiz       `json:"quiz"`
 			Questions []quizzer.Question `json:"questions"`
+			Slides    []quizzer.Slide    `json:"slides"`
+			Sections  []quizzer.Section  `json:"sections"`
 		}
 
 		err := s.db.InTx(r.Context(), func(s postgres.Session) error {
@@ -82,6 +156,18 @@ func (s *server) getQuizHandler() http.Handler {
 			}
 			quiz.Questions = questions
 
+			slides, err := s.ListSlides(r.Context(), id)
+			if err != nil {
+				return err
+			}
+			quiz.Slides = slides
+
+			sections, err := s.ListSections(r.Context(), id)
+			if err != nil {
+				return err
+			}
+			quiz.Sections = sections
+
 			return nil
 		})
 		if err != nil {
@@ -189,7 +275,17 @@ func (s *server) startQuizHandler() http.Handler {
 		quizId := mux.Vars(r)["id"]
 		userId := r.Context().Value(userIDKey).(string)
 
-		code, err := s.exectioner.CreateExecution(r.Context(), quizId, userId)
+		settings := execution.DefaultSettings()
+		if err := json.NewDecoder(r.Body).Decode(&settings); err != nil && !errors.Is(err, io.EOF) {
+			toJSONError(w, fmt.Errorf("failed to decode request body: %w", err), http.StatusBadRequest)
+			return
+		}
+		if err := settings.Validate(); err != nil {
+			toJSONError(w, fmt.Errorf("invalid settings: %w", err), http.StatusBadRequest)
+			return
+		}
+
+		code, err := s.exectioner.CreateExecution(r.Context(), quizId, userId, settings)
 		if err != nil {
 			toJSONError(w, err, http.StatusInternalServerError)
 			return
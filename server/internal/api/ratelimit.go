@@ -0,0 +1,132 @@
+package api
+
+import (
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// requestBurst is how many lookups an IP can make at once, and
+	// requestRate how many per second it can keep making.
+	requestBurst = 10
+	requestRate  = 1.0
+	// minBackoff is how long an IP is blocked after its first failed lookup.
+	// The block doubles with every further failure, up to maxBackoff.
+	minBackoff = time.Second
+	maxBackoff = 5 * time.Minute
+	// failureDecay is how long it takes for a failure to be forgotten, so
+	// that the backoff only goes down again by waiting.
+	failureDecay = 10 * time.Minute
+	// limiterEntryTTL is how long an idle IP is remembered.
+	limiterEntryTTL = 30 * time.Minute
+)
+
+// gameLimiter limits how often each IP can look up games, and backs off
+// exponentially when it asks for games that do not exist or uses the wrong
+// password, so that codes and passwords cannot be guessed.
+type gameLimiter struct {
+	mu         sync.Mutex
+	entries    map[string]*limiterEntry
+	lastPruned time.Time
+	now        func() time.Time
+}
+
+type limiterEntry struct {
+	lastSeen time.Time
+	// tokens is how many lookups the IP has left, as of lastSeen.
+	tokens       float64
+	failures     int
+	lastFailure  time.Time
+	blockedUntil time.Time
+}
+
+func newGameLimiter() *gameLimiter {
+	return &gameLimiter{entries: map[string]*limiterEntry{}, lastPruned: time.Now(), now: time.Now}
+}
+
+// allow reports whether the IP may look up a game now, and counts the lookup.
+// If not, it also returns how long the IP has to wait.
+func (l *gameLimiter) allow(ip string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.prune(now)
+
+	e := l.entry(ip, now)
+	if now.Before(e.blockedUntil) {
+		return false, e.blockedUntil.Sub(now)
+	}
+
+	if e.tokens < 1 {
+		wait := time.Duration((1 - e.tokens) / requestRate * float64(time.Second))
+		return false, wait
+	}
+	e.tokens--
+
+	return true, 0
+}
+
+// fail records a failed lookup or join and blocks the IP for a while. Earlier
+// failures count until they have decayed, also when lookups succeeded in
+// between.
+func (l *gameLimiter) fail(ip string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	e := l.entry(ip, now)
+	if e.failures > 0 {
+		e.failures = max(0, e.failures-int(now.Sub(e.lastFailure)/failureDecay))
+	}
+	e.failures++
+	e.lastFailure = now
+
+	backoff := maxBackoff
+	if e.failures < 20 {
+		backoff = min(maxBackoff, minBackoff<<(e.failures-1))
+	}
+	e.blockedUntil = now.Add(backoff)
+}
+
+// entry returns the entry of the IP, with its tokens refilled for the time
+// since it was last seen.
+func (l *gameLimiter) entry(ip string, now time.Time) *limiterEntry {
+	e, ok := l.entries[ip]
+	if !ok {
+		e = &limiterEntry{lastSeen: now, tokens: requestBurst}
+		l.entries[ip] = e
+	}
+
+	if elapsed := now.Sub(e.lastSeen); elapsed > 0 {
+		e.tokens = min(requestBurst, e.tokens+elapsed.Seconds()*requestRate)
+	}
+	e.lastSeen = now
+	return e
+}
+
+func (l *gameLimiter) prune(now time.Time) {
+	if now.Sub(l.lastPruned) < time.Minute {
+		return
+	}
+	l.lastPruned = now
+
+	for ip, e := range l.entries {
+		// IPs are only forgotten once their failures have decayed.
+		decayed := now.Sub(e.lastFailure) > time.Duration(e.failures)*failureDecay
+		if now.Sub(e.lastSeen) > limiterEntryTTL && decayed && now.After(e.blockedUntil) {
+			delete(l.entries, ip)
+		}
+	}
+}
+
+// clientIP returns the IP address the request was sent from.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,122 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGameLimiter(t *testing.T) {
+	now := time.Now()
+	newLimiter := func() *gameLimiter {
+		l := newGameLimiter()
+		l.now = func() time.Time { return now }
+		return l
+	}
+
+	t.Run("lookups are rate limited", func(t *testing.T) {
+		l := newLimiter()
+		for range requestBurst {
+			ok, _ := l.allow("1.2.3.4")
+			require.True(t, ok)
+		}
+
+		ok, wait := l.allow("1.2.3.4")
+		require.False(t, ok)
+		require.Equal(t, time.Second, wait)
+
+		ok, _ = l.allow("5.6.7.8")
+		require.True(t, ok, "other IPs are not limited")
+
+		l.now = func() time.Time { return now.Add(time.Second) }
+		ok, _ = l.allow("1.2.3.4")
+		require.True(t, ok)
+		ok, _ = l.allow("1.2.3.4")
+		require.False(t, ok)
+	})
+
+	t.Run("failures back off exponentially", func(t *testing.T) {
+		tests := []struct {
+			failures int
+			wait     time.Duration
+		}{
+			{failures: 1, wait: time.Second},
+			{failures: 2, wait: 2 * time.Second},
+			{failures: 5, wait: 16 * time.Second},
+			{failures: 9, wait: 256 * time.Second},
+			{failures: 10, wait: maxBackoff},
+			{failures: 100, wait: maxBackoff},
+		}
+		for _, tt := range tests {
+			l := newLimiter()
+			for range tt.failures {
+				l.fail("1.2.3.4")
+			}
+
+			ok, wait := l.allow("1.2.3.4")
+			require.False(t, ok)
+			require.Equal(t, tt.wait, wait)
+
+			ok, _ = l.allow("5.6.7.8")
+			require.True(t, ok, "other IPs are not blocked")
+		}
+	})
+
+	t.Run("block ends", func(t *testing.T) {
+		l := newLimiter()
+		l.fail("1.2.3.4")
+		l.now = func() time.Time { return now.Add(time.Second) }
+
+		ok, _ := l.allow("1.2.3.4")
+		require.True(t, ok)
+	})
+
+	t.Run("successful lookups do not reset backoff", func(t *testing.T) {
+		// E.g. an IP that knows the code of a game and guesses its password.
+		l := newLimiter()
+		for i := range 3 {
+			l.now = func() time.Time { return now.Add(time.Duration(i) * 5 * time.Second) }
+			ok, _ := l.allow("1.2.3.4")
+			require.True(t, ok)
+			l.fail("1.2.3.4")
+		}
+
+		_, wait := l.allow("1.2.3.4")
+		require.Equal(t, 4*time.Second, wait)
+	})
+
+	t.Run("failures decay", func(t *testing.T) {
+		l := newLimiter()
+		for range 3 {
+			l.fail("1.2.3.4")
+		}
+
+		l.now = func() time.Time { return now.Add(2 * failureDecay) }
+		l.fail("1.2.3.4")
+		_, wait := l.allow("1.2.3.4")
+		require.Equal(t, 2*time.Second, wait)
+	})
+
+	t.Run("idle IPs are forgotten", func(t *testing.T) {
+		l := newLimiter()
+		l.fail("1.2.3.4")
+		l.now = func() time.Time { return now.Add(limiterEntryTTL + time.Minute) }
+
+		ok, _ := l.allow("5.6.7.8")
+		require.True(t, ok)
+		require.NotContains(t, l.entries, "1.2.3.4")
+	})
+
+	t.Run("IPs are remembered until their failures decayed", func(t *testing.T) {
+		l := newLimiter()
+		for range 5 {
+			l.fail("1.2.3.4")
+		}
+		l.now = func() time.Time { return now.Add(limiterEntryTTL + time.Minute) }
+
+		ok, _ := l.allow("5.6.7.8")
+		require.True(t, ok)
+		require.Contains(t, l.entries, "1.2.3.4")
+	})
+}
@@ -0,0 +1,242 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog/log"
+	"github.com/william-joh/quizzer/server/internal/execution"
+	"github.com/william-joh/quizzer/server/internal/postgres"
+	"github.com/william-joh/quizzer/server/internal/quizzer"
+)
+
+var (
+	errScheduledGameNotFound = errors.New("scheduled game not found")
+	errScheduledGameOpened   = errors.New("scheduled game is no longer pending")
+)
+
+func scheduleErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, errScheduledGameNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, errScheduledGameOpened):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// scheduleRequest is the start time and settings of a scheduled game. The
+// settings may be left out when rescheduling, to keep the current ones.
+type scheduleRequest struct {
+	StartAt  time.Time       `json:"startAt"`
+	Settings json.RawMessage `json:"settings"`
+}
+
+// settings returns the settings of the request, with the settings it leaves
+// out set to their defaults as when a game is started right away. It returns
+// nil if the request has no settings.
+func (req scheduleRequest) settings() (*execution.Settings, error) {
+	if len(req.Settings) == 0 || string(req.Settings) == "null" {
+		return nil, nil
+	}
+
+	settings := execution.DefaultSettings()
+	if err := json.Unmarshal(req.Settings, &settings); err != nil {
+		return nil, fmt.Errorf("invalid settings: %w", err)
+	}
+	return &settings, nil
+}
+
+func (req scheduleRequest) validate() error {
+	if !req.StartAt.After(time.Now()) {
+		return errors.New("start time must be in the future")
+	}
+
+	settings, err := req.settings()
+	if err != nil {
+		return err
+	}
+	if settings != nil {
+		if err := settings.Validate(); err != nil {
+			return fmt.Errorf("invalid settings: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (s *server) createScheduledGameHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID := r.Context().Value(userIDKey).(string)
+
+		var req struct {
+			QuizID string `json:"quizId"`
+			scheduleRequest
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			toJSONError(w, fmt.Errorf("failed to decode request body: %w", err), http.StatusBadRequest)
+			return
+		}
+		if err := req.validate(); err != nil {
+			toJSONError(w, err, http.StatusBadRequest)
+			return
+		}
+
+		settings := execution.DefaultSettings()
+		// The settings decoded when the request was validated.
+		if custom, _ := req.settings(); custom != nil {
+			settings = *custom
+		}
+		settingsJSON, err := json.Marshal(settings)
+		if err != nil {
+			toJSONError(w, fmt.Errorf("failed to encode settings: %w", err), http.StatusInternalServerError)
+			return
+		}
+
+		quiz, err := s.db.Do(r.Context()).GetQuiz(r.Context(), req.QuizID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			toJSONError(w, errors.New("quiz not found"), http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			toJSONError(w, fmt.Errorf("failed to get quiz: %w", err), http.StatusInternalServerError)
+			return
+		}
+		if quiz.CreatedBy != userID {
+			toJSONError(w, errors.New("access denied"), http.StatusForbidden)
+			return
+		}
+
+		game := quizzer.ScheduledGame{
+			ID:       uuid.New().String(),
+			QuizID:   req.QuizID,
+			HostID:   userID,
+			StartAt:  req.StartAt,
+			Settings: settingsJSON,
+			Status:   quizzer.ScheduledGameStatusPending,
+		}
+		if err := s.db.Do(r.Context()).CreateScheduledGame(r.Context(), game); err != nil {
+			toJSONError(w, fmt.Errorf("failed to schedule game: %w", err), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		if err := json.NewEncoder(w).Encode(game); err != nil {
+			log.Error().Err(err).Msg("failed to encode response")
+		}
+	})
+}
+
+func (s *server) listScheduledGamesHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID := r.Context().Value(userIDKey).(string)
+
+		games, err := s.db.Do(r.Context()).ListScheduledGames(r.Context(), userID)
+		if err != nil {
+			toJSONError(w, fmt.Errorf("failed to list scheduled games: %w", err), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(games); err != nil {
+			log.Error().Err(err).Msg("failed to encode response")
+		}
+	})
+}
+
+// getPendingScheduledGame returns the scheduled game if it belongs to the user
+// and has not been opened yet. The game is locked until the transaction ends,
+// so that the scheduler cannot open it while it is changed.
+func getPendingScheduledGame(r *http.Request, s postgres.Session, id, userID string) (quizzer.ScheduledGame, error) {
+	game, err := s.LockScheduledGame(r.Context(), id)
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && game.HostID != userID) {
+		return quizzer.ScheduledGame{}, errScheduledGameNotFound
+	}
+	if err != nil {
+		return quizzer.ScheduledGame{}, fmt.Errorf("get scheduled game: %w", err)
+	}
+
+	if game.Status != quizzer.ScheduledGameStatusPending {
+		return quizzer.ScheduledGame{}, errScheduledGameOpened
+	}
+
+	return game, nil
+}
+
+func (s *server) rescheduleGameHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := mux.Vars(r)["id"]
+		userID := r.Context().Value(userIDKey).(string)
+
+		var req scheduleRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			toJSONError(w, fmt.Errorf("failed to decode request body: %w", err), http.StatusBadRequest)
+			return
+		}
+		if err := req.validate(); err != nil {
+			toJSONError(w, err, http.StatusBadRequest)
+			return
+		}
+
+		var game quizzer.ScheduledGame
+		err := s.db.InTx(r.Context(), func(s postgres.Session) error {
+			var err error
+			game, err = getPendingScheduledGame(r, s, id, userID)
+			if err != nil {
+				return err
+			}
+
+			game.StartAt = req.StartAt
+			settings, err := req.settings()
+			if err != nil {
+				return err
+			}
+			if settings != nil {
+				game.Settings, err = json.Marshal(settings)
+				if err != nil {
+					return fmt.Errorf("encode settings: %w", err)
+				}
+			}
+
+			return s.UpdateScheduledGame(r.Context(), game)
+		})
+		if err != nil {
+			toJSONError(w, err, scheduleErrorStatus(err))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(game); err != nil {
+			log.Error().Err(err).Msg("failed to encode response")
+		}
+	})
+}
+
+func (s *server) cancelScheduledGameHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := mux.Vars(r)["id"]
+		userID := r.Context().Value(userIDKey).(string)
+
+		err := s.db.InTx(r.Context(), func(s postgres.Session) error {
+			if _, err := getPendingScheduledGame(r, s, id, userID); err != nil {
+				return err
+			}
+
+			return s.DeleteScheduledGame(r.Context(), id)
+		})
+		if err != nil {
+			toJSONError(w, err, scheduleErrorStatus(err))
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	})
+}
@@ -0,0 +1,192 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/rs/zerolog/log"
+	"github.com/william-joh/quizzer/server/internal/execution"
+)
+
+// sseKeepAlive is how often a comment is sent on an idle event stream, so that
+// proxies do not close it.
+const sseKeepAlive = 15 * time.Second
+
+// sseConn sends the state of a quiz as server-sent events, for participants
+// on networks that block websockets.
+type sseConn struct {
+	mu     sync.Mutex
+	w      http.ResponseWriter
+	rc     *http.ResponseController
+	closed chan struct{}
+	once   sync.Once
+}
+
+func newSSEConn(w http.ResponseWriter) *sseConn {
+	return &sseConn{
+		w:      w,
+		rc:     http.NewResponseController(w),
+		closed: make(chan struct{}),
+	}
+}
+
+func (c *sseConn) WriteJSON(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("encode event: %w", err)
+	}
+
+	return c.write(fmt.Sprintf("data: %s\n\n", data))
+}
+
+// Close tells the participant that the quiz has ended and ends the stream.
+func (c *sseConn) Close() error {
+	var err error
+	c.once.Do(func() {
+		err = c.write("event: end\ndata: QUIZ_END\n\n")
+
+		c.mu.Lock()
+		close(c.closed)
+		c.mu.Unlock()
+	})
+	return err
+}
+
+// stop ends the stream without telling the participant, once the request is
+// done and nothing may be written to it anymore.
+func (c *sseConn) stop() {
+	c.once.Do(func() {
+		c.mu.Lock()
+		close(c.closed)
+		c.mu.Unlock()
+	})
+}
+
+func (c *sseConn) write(event string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	select {
+	case <-c.closed:
+		return errors.New("event stream closed")
+	default:
+	}
+
+	if _, err := fmt.Fprint(c.w, event); err != nil {
+		return fmt.Errorf("write event: %w", err)
+	}
+	return c.rc.Flush()
+}
+
+func (s *server) eventsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		e, ip, ok := s.lookupGame(w, r)
+		if !ok {
+			return
+		}
+
+		query := r.URL.Query()
+		identity, err := s.identify(r)
+		if err != nil && query.Get("role") != execution.RoleSpectator {
+			toJSONError(w, fmt.Errorf("identify participant: %w", err), http.StatusUnauthorized)
+			return
+		}
+
+		conn := newSSEConn(w)
+		// The stream stays open for the whole quiz, which is longer than the
+		// write timeout of the server.
+		if err := conn.rc.SetWriteDeadline(time.Time{}); err != nil {
+			log.Warn().Err(err).Msg("failed to clear write deadline of event stream")
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("X-Accel-Buffering", "no")
+
+		// EventSource cannot send a body, so the join data is in the query.
+		join := execution.Message{
+			Type: "Join",
+			Data: map[string]interface{}{
+				"role":     query.Get("role"),
+				"password": query.Get("password"),
+			},
+		}
+		if err := e.HandleMessage(conn, identity, join); err != nil {
+			conn.stop()
+			if errors.Is(err, execution.ErrWrongPassword) {
+				s.gameLimiter.fail(ip)
+				toJSONError(w, err, http.StatusForbidden)
+				return
+			}
+			toJSONError(w, err, http.StatusConflict)
+			return
+		}
+
+		ticker := time.NewTicker(sseKeepAlive)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-conn.closed:
+				log.Debug().Msg("event stream closed by the quiz")
+				return
+			case <-r.Context().Done():
+				log.Debug().Msg("event stream closed by the client")
+				if err := e.Disconnect(conn); err != nil {
+					log.Error().Err(err).Msg("failed to disconnect event stream")
+				}
+				conn.stop()
+				return
+			case <-ticker.C:
+				if err := conn.write(": keep-alive\n\n"); err != nil {
+					log.Debug().Err(err).Msg("failed to send keep-alive")
+				}
+			}
+		}
+	})
+}
+
+// actionsHandler handles the messages of participants that follow the quiz
+// with server-sent events, which cannot send messages themselves. Only
+// participants that joined can act, so the game is not looked up through the
+// limiter, which is there for those that try to join.
+func (s *server) actionsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		identity, err := s.identify(r)
+		if err != nil {
+			toJSONError(w, fmt.Errorf("identify participant: %w", err), http.StatusUnauthorized)
+			return
+		}
+
+		e, err := s.exectioner.GetExecution(r.Context(), mux.Vars(r)["code"])
+		if err != nil {
+			toJSONError(w, err, http.StatusNotFound)
+			return
+		}
+
+		var msg execution.Message
+		if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+			toJSONError(w, fmt.Errorf("decode request body: %w", err), http.StatusBadRequest)
+			return
+		}
+
+		if err := e.HandleAction(identity, msg); err != nil {
+			if errors.Is(err, execution.ErrNotJoined) {
+				toJSONError(w, err, http.StatusForbidden)
+				return
+			}
+			if errors.Is(err, execution.ErrRateLimited) {
+				toJSONError(w, err, http.StatusTooManyRequests)
+				return
+			}
+			toJSONError(w, err, http.StatusConflict)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
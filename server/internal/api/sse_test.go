@@ -0,0 +1,185 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/william-joh/quizzer/server/internal/execution"
+)
+
+// readEvent reads the next event from the stream, skipping comments. It
+// returns the name of the event, which is empty for state updates, and its
+// data.
+func readEvent(t *testing.T, r *bufio.Reader) (string, string) {
+	var name, data string
+	for {
+		line, err := r.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimSuffix(line, "\n")
+
+		switch {
+		case line == "" && data != "":
+			return name, data
+		case strings.HasPrefix(line, "event: "):
+			name = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+// readPhase reads state updates from the stream until one is in the phase.
+func readPhase(t *testing.T, r *bufio.Reader, phase execution.Phase) {
+	for {
+		name, data := readEvent(t, r)
+		require.Empty(t, name)
+
+		var state map[string]any
+		require.NoError(t, json.Unmarshal([]byte(data), &state))
+		if state["phase"] == string(phase) {
+			return
+		}
+	}
+}
+
+func TestEvents(t *testing.T) {
+	// open opens the event stream of the game at the URL with the query, and
+	// returns the response.
+	open := func(t *testing.T, ctx context.Context, gameURL string, query url.Values) *http.Response {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, gameURL+"/events?"+query.Encode(), nil)
+		require.NoError(t, err)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+
+	t.Run("participant follows the quiz", func(t *testing.T) {
+		s, _, code := newTestServer(t, execution.DefaultSettings())
+		server := httptest.NewServer(s.Handler())
+		t.Cleanup(server.Close)
+		e, err := s.exectioner.GetExecution(context.Background(), code)
+		require.NoError(t, err)
+		token, _, err := s.guestTokens.issue("guest")
+		require.NoError(t, err)
+
+		resp := open(t, context.Background(), server.URL+"/game/"+code, url.Values{"guestToken": {token}})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+		events := bufio.NewReader(resp.Body)
+		readPhase(t, events, execution.PhaseLobby)
+
+		require.NoError(t, e.Control("host-id", execution.ActionStart))
+		readPhase(t, events, execution.PhaseQuestion)
+
+		// Answers are sent as actions, next to the stream.
+		req, err := http.NewRequest(http.MethodPost, server.URL+"/game/"+code+"/actions", strings.NewReader(`{"type": "AnswerQuestion", "data": {"optionId": "o1"}}`))
+		require.NoError(t, err)
+		req.Header.Set(guestTokenHeader, token)
+		actionResp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		actionResp.Body.Close()
+		require.Equal(t, http.StatusNoContent, actionResp.StatusCode)
+		// The only participant answered, so the question is over.
+		readPhase(t, events, execution.PhaseResults)
+
+		require.NoError(t, e.Control("host-id", execution.ActionEnd))
+		name, data := readEvent(t, events)
+		require.Equal(t, "end", name)
+		require.Equal(t, "QUIZ_END", data)
+		_, err = events.ReadString('\n')
+		require.Error(t, err, "stream must end with the quiz")
+	})
+
+	t.Run("spectator without an account", func(t *testing.T) {
+		s, _, code := newTestServer(t, execution.DefaultSettings())
+		server := httptest.NewServer(s.Handler())
+		t.Cleanup(server.Close)
+
+		resp := open(t, context.Background(), server.URL+"/game/"+code, url.Values{"role": {execution.RoleSpectator}})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		readPhase(t, bufio.NewReader(resp.Body), execution.PhaseLobby)
+	})
+
+	t.Run("closing the stream leaves the quiz", func(t *testing.T) {
+		s, _, code := newTestServer(t, execution.DefaultSettings())
+		server := httptest.NewServer(s.Handler())
+		t.Cleanup(server.Close)
+		e, err := s.exectioner.GetExecution(context.Background(), code)
+		require.NoError(t, err)
+		token, _, err := s.guestTokens.issue("guest")
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		resp := open(t, ctx, server.URL+"/game/"+code, url.Values{"guestToken": {token}})
+		readPhase(t, bufio.NewReader(resp.Body), execution.PhaseLobby)
+		status, err := e.Status("host-id")
+		require.NoError(t, err)
+		require.Equal(t, 1, status.NrParticipants)
+
+		cancel()
+		require.Eventually(t, func() bool {
+			status, err := e.Status("host-id")
+			return err == nil && status.NrParticipants == 0
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+
+	withPassword := execution.DefaultSettings()
+	withPassword.JoinPassword = "secret"
+
+	tests := []struct {
+		name     string
+		settings execution.Settings
+		code     string
+		query    url.Values
+		status   int
+	}{
+		{
+			name:     "unknown game",
+			settings: execution.DefaultSettings(),
+			code:     "unknown",
+			query:    url.Values{"role": {execution.RoleSpectator}},
+			status:   http.StatusNotFound,
+		},
+		{
+			name:     "not logged in",
+			settings: execution.DefaultSettings(),
+			status:   http.StatusUnauthorized,
+		},
+		{
+			name:     "wrong password",
+			settings: withPassword,
+			query:    url.Values{"role": {execution.RoleSpectator}, "password": {"guess"}},
+			status:   http.StatusForbidden,
+		},
+		{
+			name:     "password",
+			settings: withPassword,
+			query:    url.Values{"role": {execution.RoleSpectator}, "password": {"secret"}},
+			status:   http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _, code := newTestServer(t, tt.settings)
+			server := httptest.NewServer(s.Handler())
+			t.Cleanup(server.Close)
+			if tt.code != "" {
+				code = tt.code
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			resp := open(t, ctx, server.URL+"/game/"+code, tt.query)
+			require.Equal(t, tt.status, resp.StatusCode)
+		})
+	}
+}
@@ -1,11 +1,17 @@
 package api
 
 import (
+	"errors"
+	"fmt"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/gorilla/websocket"
 	"github.com/rs/zerolog/log"
+	"github.com/william-joh/quizzer/server/internal/execution"
 )
 
 func (s *server) wsHandler() http.Handler {
@@ -16,12 +22,17 @@ func (s *server) wsHandler() http.Handler {
 	}
 
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		code := mux.Vars(r)["code"]
-		log.Info().Str("code", code).Msg("WebSocket connection")
+		log.Info().Str("code", mux.Vars(r)["code"]).Msg("WebSocket connection")
 
-		e, err := s.exectioner.GetExecution(r.Context(), code)
-		if err != nil {
-			toJSONError(w, err, http.StatusInternalServerError)
+		e, ip, ok := s.lookupGame(w, r)
+		if !ok {
+			return
+		}
+
+		// Spectators may connect without logging in, e.g. from a projector.
+		identity, err := s.identify(r)
+		if err != nil && r.URL.Query().Get("role") != execution.RoleSpectator {
+			toJSONError(w, fmt.Errorf("identify participant: %w", err), http.StatusUnauthorized)
 			return
 		}
 
@@ -33,13 +44,47 @@ func (s *server) wsHandler() http.Handler {
 		}
 		defer conn.Close()
 
-		// Handle the WebSocket connection
+		// Whatever ends the connection, the player leaves the game.
+		defer e.Disconnect(conn)
+
+		// Handle the WebSocket connection. Messages that are rejected have
+		// been answered with an error, and the connection is kept.
 		for {
-			err := e.HandleMessages(conn)
-			if err != nil {
-				log.Error().Err(err).Msg("failed to handle message")
+			err := e.HandleMessages(conn, identity)
+			if errors.Is(err, execution.ErrClosed) {
+				log.Debug().Err(err).Msg("WebSocket connection closed")
+				break
+			}
+			if errors.Is(err, execution.ErrWrongPassword) {
+				// Passwords cannot be guessed by retrying on one connection.
+				s.gameLimiter.fail(ip)
 				break
 			}
+			if err != nil && !errors.Is(err, execution.ErrRateLimited) {
+				log.Debug().Err(err).Msg("rejected message")
+			}
 		}
 	})
 }
+
+// lookupGame returns the execution with the code in the URL, and the IP of the
+// caller. Lookups are rate limited per IP, and IPs that failed lookups are
+// blocked for a while, so that codes cannot be guessed. If the lookup fails the
+// error has been written and false is returned.
+func (s *server) lookupGame(w http.ResponseWriter, r *http.Request) (*execution.Execution, string, bool) {
+	ip := clientIP(r)
+	if ok, wait := s.gameLimiter.allow(ip); !ok {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+		toJSONError(w, fmt.Errorf("too many requests, retry in %s", wait.Round(time.Second)), http.StatusTooManyRequests)
+		return nil, ip, false
+	}
+
+	e, err := s.exectioner.GetExecution(r.Context(), mux.Vars(r)["code"])
+	if err != nil {
+		s.gameLimiter.fail(ip)
+		toJSONError(w, err, http.StatusNotFound)
+		return nil, ip, false
+	}
+
+	return e, ip, true
+}
@@ -0,0 +1,96 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+)
+
+var (
+	// ErrNotFound is returned when opening a blob that does not exist.
+	ErrNotFound = errors.New("blob not found")
+	// ErrTooLarge is returned when storing content larger than allowed.
+	ErrTooLarge = errors.New("blob too large")
+	// ErrUnsupportedType is returned when storing content that is not one of
+	// the supported images or audio formats.
+	ErrUnsupportedType = errors.New("unsupported media type")
+)
+
+// BlobStore stores uploaded media by their content, so that storing the same
+// content twice gives the same blob.
+type BlobStore interface {
+	// Put stores the content and returns its blob. The type of the content
+	// is detected from the content itself.
+	Put(ctx context.Context, r io.Reader) (Blob, error)
+	// Open returns the content of the blob with the key. The caller closes
+	// it.
+	Open(ctx context.Context, key string) (io.ReadSeekCloser, Blob, error)
+	// MaxSize is the largest content that can be stored, in bytes.
+	MaxSize() int64
+}
+
+// Blob is stored media.
+type Blob struct {
+	// Key identifies the blob. It is derived from the content of the blob.
+	Key         string `json:"key"`
+	ContentType string `json:"contentType"`
+	Size        int64  `json:"size"`
+	// Thumbnail is the key of a smaller version of an image. It is empty for
+	// other media.
+	Thumbnail string `json:"thumbnail,omitempty"`
+}
+
+// extensions are the supported content types and the file extension blobs of
+// them are stored with.
+var extensions = map[string]string{
+	"image/png":       "png",
+	"image/jpeg":      "jpg",
+	"image/gif":       "gif",
+	"audio/mpeg":      "mp3",
+	"audio/wave":      "wav",
+	"application/ogg": "ogg",
+}
+
+// IsImage reports whether the blob is an image.
+func (b Blob) IsImage() bool {
+	return strings.HasPrefix(b.ContentType, "image/")
+}
+
+// detectContentType returns the content type of the data. MP3 files without
+// an ID3 tag start directly with an MPEG audio frame, which
+// http.DetectContentType does not recognise.
+func detectContentType(data []byte) string {
+	contentType := http.DetectContentType(data)
+	if contentType == "application/octet-stream" && isMPEGFrame(data) {
+		return "audio/mpeg"
+	}
+	return contentType
+}
+
+// isMPEGFrame reports whether the data starts with a valid MPEG audio frame
+// header.
+func isMPEGFrame(data []byte) bool {
+	if len(data) < 4 || data[0] != 0xff || data[1]&0xe0 != 0xe0 {
+		return false
+	}
+
+	version := data[1] >> 3 & 0x3
+	layer := data[1] >> 1 & 0x3
+	bitrate := data[2] >> 4
+	sampleRate := data[2] >> 2 & 0x3
+	emphasis := data[3] & 0x3
+	return version != 0x1 && layer != 0x0 && bitrate != 0x0 && bitrate != 0xf &&
+		sampleRate != 0x3 && emphasis != 0x2
+}
+
+// contentType returns the content type of blobs stored with the extension.
+func contentType(ext string) (string, bool) {
+	for t, e := range extensions {
+		if e == ext {
+			return t, true
+		}
+	}
+	return "", false
+}
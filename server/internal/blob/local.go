@@ -0,0 +1,215 @@
+package blob
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	mediaDirEnv     = "QUIZZER_MEDIA_DIR"
+	mediaMaxSizeEnv = "QUIZZER_MEDIA_MAX_SIZE"
+)
+
+// keyPattern matches the keys of stored blobs, so that keys from requests
+// cannot point outside of the store.
+var keyPattern = regexp.MustCompile(`^[0-9a-f]{64}\.([a-z0-9]+)$`)
+
+// LocalConfig configures a blob store on the local filesystem.
+type LocalConfig struct {
+	// Dir is the directory the blobs are stored in.
+	Dir string
+	// MaxSize is the largest blob that can be stored, in bytes.
+	MaxSize int64
+}
+
+func DefaultLocalConfig() LocalConfig {
+	return LocalConfig{Dir: "media", MaxSize: 10 << 20}
+}
+
+// LocalConfigFromEnv returns the default local config, overridden by the
+// QUIZZER_MEDIA_DIR and QUIZZER_MEDIA_MAX_SIZE environment variables if set.
+func LocalConfigFromEnv() (LocalConfig, error) {
+	config := DefaultLocalConfig()
+	if dir := os.Getenv(mediaDirEnv); dir != "" {
+		config.Dir = dir
+	}
+
+	if size := os.Getenv(mediaMaxSizeEnv); size != "" {
+		s, err := strconv.ParseInt(size, 10, 64)
+		if err != nil {
+			return LocalConfig{}, fmt.Errorf("parse %s: %w", mediaMaxSizeEnv, err)
+		}
+		config.MaxSize = s
+	}
+
+	return config, config.Validate()
+}
+
+func (c LocalConfig) Validate() error {
+	if c.Dir == "" {
+		return errors.New("media directory cannot be empty")
+	}
+
+	if c.MaxSize <= 0 {
+		return errors.New("media max size must be positive")
+	}
+
+	return nil
+}
+
+var _ BlobStore = &Local{}
+
+// Local stores blobs as files in a directory, named by the SHA-256 of their
+// content.
+type Local struct {
+	config LocalConfig
+}
+
+// NewLocal returns a blob store in the configured directory, creating it if
+// needed.
+func NewLocal(config LocalConfig) (*Local, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(config.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create media directory: %w", err)
+	}
+
+	return &Local{config: config}, nil
+}
+
+func (l *Local) Put(ctx context.Context, r io.Reader) (Blob, error) {
+	// Reading one byte more than allowed tells whether there is too much.
+	data, err := io.ReadAll(io.LimitReader(r, l.config.MaxSize+1))
+	if err != nil {
+		return Blob{}, fmt.Errorf("read blob: %w", err)
+	}
+	if int64(len(data)) > l.config.MaxSize {
+		return Blob{}, fmt.Errorf("%w: blobs can be at most %d bytes", ErrTooLarge, l.config.MaxSize)
+	}
+
+	contentType := detectContentType(data)
+	ext, ok := extensions[contentType]
+	if !ok {
+		log.Error().Str("contentType", contentType).Msg("Unsupported media type")
+		return Blob{}, fmt.Errorf("%w: %s", ErrUnsupportedType, contentType)
+	}
+
+	blob := Blob{ContentType: contentType}
+	var thumb []byte
+	if blob.IsImage() {
+		// Decoding the image also checks that it is not just something
+		// that starts like one.
+		thumb, err = thumbnail(data)
+		if err != nil {
+			return Blob{}, err
+		}
+	}
+
+	blob, err = l.write(data, contentType, ext)
+	if err != nil {
+		return Blob{}, err
+	}
+
+	if thumb != nil {
+		t, err := l.write(thumb, "image/png", extensions["image/png"])
+		if err != nil {
+			return Blob{}, err
+		}
+		blob.Thumbnail = t.Key
+	}
+
+	return blob, nil
+}
+
+func (l *Local) MaxSize() int64 {
+	return l.config.MaxSize
+}
+
+// write stores the content under its key, unless it is already stored.
+func (l *Local) write(data []byte, contentType, ext string) (Blob, error) {
+	sum := sha256.Sum256(data)
+	blob := Blob{
+		Key:         hex.EncodeToString(sum[:]) + "." + ext,
+		ContentType: contentType,
+		Size:        int64(len(data)),
+	}
+
+	path := l.path(blob.Key)
+	if _, err := os.Stat(path); err == nil {
+		return blob, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return Blob{}, fmt.Errorf("create blob directory: %w", err)
+	}
+
+	// Writing to a temporary file first means a blob is never seen half
+	// written.
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return Blob{}, fmt.Errorf("create blob: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, bytes.NewReader(data)); err != nil {
+		tmp.Close()
+		return Blob{}, fmt.Errorf("write blob: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return Blob{}, fmt.Errorf("write blob: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return Blob{}, fmt.Errorf("store blob: %w", err)
+	}
+
+	return blob, nil
+}
+
+func (l *Local) Open(ctx context.Context, key string) (io.ReadSeekCloser, Blob, error) {
+	m := keyPattern.FindStringSubmatch(key)
+	if m == nil {
+		return nil, Blob{}, ErrNotFound
+	}
+	contentType, ok := contentType(m[1])
+	if !ok {
+		return nil, Blob{}, ErrNotFound
+	}
+
+	f, err := os.Open(l.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, Blob{}, ErrNotFound
+	}
+	if err != nil {
+		return nil, Blob{}, fmt.Errorf("open blob: %w", err)
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, Blob{}, fmt.Errorf("stat blob: %w", err)
+	}
+
+	return f, Blob{Key: key, ContentType: contentType, Size: info.Size()}, nil
+}
+
+// path returns where the blob with the key is stored. Blobs are spread over
+// subdirectories by the start of their key, so that no directory gets too
+// large.
+func (l *Local) path(key string) string {
+	return filepath.Join(l.config.Dir, key[:2], key)
+}
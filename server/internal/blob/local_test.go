@@ -0,0 +1,148 @@
+package blob_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/william-joh/quizzer/server/internal/blob"
+)
+
+func TestLocal(t *testing.T) {
+	store, err := blob.NewLocal(blob.LocalConfig{Dir: t.TempDir(), MaxSize: 1 << 20})
+	require.NoError(t, err)
+
+	var img bytes.Buffer
+	require.NoError(t, png.Encode(&img, image.NewRGBA(image.Rect(0, 0, 1024, 512))))
+
+	t.Run("put image", func(t *testing.T) {
+		b, err := store.Put(context.Background(), bytes.NewReader(img.Bytes()))
+		require.NoError(t, err)
+		require.Equal(t, "image/png", b.ContentType)
+		require.Equal(t, int64(img.Len()), b.Size)
+		require.True(t, strings.HasSuffix(b.Key, ".png"))
+		require.NotEmpty(t, b.Thumbnail)
+
+		again, err := store.Put(context.Background(), bytes.NewReader(img.Bytes()))
+		require.NoError(t, err)
+		require.Equal(t, b, again)
+
+		f, opened, err := store.Open(context.Background(), b.Key)
+		require.NoError(t, err)
+		defer f.Close()
+		require.Equal(t, b.Key, opened.Key)
+		require.Equal(t, "image/png", opened.ContentType)
+		data, err := io.ReadAll(f)
+		require.NoError(t, err)
+		require.Equal(t, img.Bytes(), data)
+
+		thumb, _, err := store.Open(context.Background(), b.Thumbnail)
+		require.NoError(t, err)
+		defer thumb.Close()
+		config, err := png.DecodeConfig(thumb)
+		require.NoError(t, err)
+		require.Equal(t, 256, config.Width)
+		require.Equal(t, 128, config.Height)
+	})
+
+	t.Run("put photo", func(t *testing.T) {
+		// The left half of the photo is red and the right half blue.
+		photo := image.NewRGBA(image.Rect(0, 0, 600, 800))
+		for y := range 800 {
+			for x := range 600 {
+				c := color.RGBA{R: 255, A: 255}
+				if x >= 300 {
+					c = color.RGBA{B: 255, A: 255}
+				}
+				photo.Set(x, y, c)
+			}
+		}
+		var data bytes.Buffer
+		require.NoError(t, jpeg.Encode(&data, photo, &jpeg.Options{Quality: 90}))
+
+		b, err := store.Put(context.Background(), bytes.NewReader(data.Bytes()))
+		require.NoError(t, err)
+		require.Equal(t, "image/jpeg", b.ContentType)
+
+		f, _, err := store.Open(context.Background(), b.Thumbnail)
+		require.NoError(t, err)
+		defer f.Close()
+		thumb, err := png.Decode(f)
+		require.NoError(t, err)
+		require.Equal(t, image.Rect(0, 0, 192, 256), thumb.Bounds())
+
+		red, _, blue, _ := thumb.At(40, 128).RGBA()
+		require.Greater(t, red, blue)
+		red, _, blue, _ = thumb.At(150, 128).RGBA()
+		require.Greater(t, blue, red)
+	})
+
+	t.Run("put image with too many pixels", func(t *testing.T) {
+		// A small file can claim to be a huge image, which is rejected
+		// before it is decoded.
+		var small bytes.Buffer
+		require.NoError(t, png.Encode(&small, image.NewGray(image.Rect(0, 0, 1, 1))))
+		huge := bytes.Clone(small.Bytes())
+		// The header chunk follows the 8 byte signature, its length and
+		// its type.
+		binary.BigEndian.PutUint32(huge[16:], 10_000)
+		binary.BigEndian.PutUint32(huge[20:], 10_000)
+		binary.BigEndian.PutUint32(huge[29:], crc32.ChecksumIEEE(huge[12:29]))
+
+		_, err := store.Put(context.Background(), bytes.NewReader(huge))
+		require.ErrorIs(t, err, blob.ErrTooLarge)
+	})
+
+	t.Run("put audio", func(t *testing.T) {
+		b, err := store.Put(context.Background(), strings.NewReader("ID3\x03\x00\x00\x00\x00\x00\x00"))
+		require.NoError(t, err)
+		require.Equal(t, "audio/mpeg", b.ContentType)
+		require.Empty(t, b.Thumbnail)
+	})
+
+	t.Run("put bare mpeg frame", func(t *testing.T) {
+		frame := append([]byte{0xff, 0xfb, 0x90, 0x64}, make([]byte, 413)...)
+		b, err := store.Put(context.Background(), bytes.NewReader(frame))
+		require.NoError(t, err)
+		require.Equal(t, "audio/mpeg", b.ContentType)
+		require.True(t, strings.HasSuffix(b.Key, ".mp3"))
+	})
+
+	t.Run("put invalid mpeg frame", func(t *testing.T) {
+		// A bitrate index of 1111 is not allowed.
+		_, err := store.Put(context.Background(), bytes.NewReader([]byte{0xff, 0xfb, 0xf0, 0x64}))
+		require.ErrorIs(t, err, blob.ErrUnsupportedType)
+	})
+
+	t.Run("put unsupported type", func(t *testing.T) {
+		_, err := store.Put(context.Background(), strings.NewReader("<html><body>hi</body></html>"))
+		require.ErrorIs(t, err, blob.ErrUnsupportedType)
+	})
+
+	t.Run("put broken image", func(t *testing.T) {
+		_, err := store.Put(context.Background(), bytes.NewReader(img.Bytes()[:100]))
+		require.ErrorIs(t, err, blob.ErrUnsupportedType)
+	})
+
+	t.Run("put too large", func(t *testing.T) {
+		_, err := store.Put(context.Background(), bytes.NewReader(make([]byte, 1<<20+1)))
+		require.ErrorIs(t, err, blob.ErrTooLarge)
+	})
+
+	t.Run("open non-existing blob", func(t *testing.T) {
+		_, _, err := store.Open(context.Background(), strings.Repeat("a", 64)+".png")
+		require.ErrorIs(t, err, blob.ErrNotFound)
+
+		_, _, err = store.Open(context.Background(), "../../etc/passwd")
+		require.ErrorIs(t, err, blob.ErrNotFound)
+	})
+}
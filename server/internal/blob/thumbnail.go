@@ -0,0 +1,60 @@
+package blob
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	"image/png"
+
+	"golang.org/x/image/draw"
+)
+
+const (
+	// thumbnailSize is the width and height thumbnails fit in.
+	thumbnailSize = 256
+	// maxPixels limits the size of images that are decoded, so that a small
+	// file cannot claim a huge image. It is checked before decoding, and
+	// allows the photos of most cameras.
+	maxPixels = 25_000_000
+)
+
+// thumbnail returns a PNG of the image scaled down to fit the thumbnail size.
+// Images that already fit are not scaled up.
+func thumbnail(data []byte) ([]byte, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid image: %w", ErrUnsupportedType, err)
+	}
+	if config.Width*config.Height > maxPixels {
+		return nil, fmt.Errorf("%w: image is %dx%d pixels", ErrTooLarge, config.Width, config.Height)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid image: %w", ErrUnsupportedType, err)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, scaleDown(img, thumbnailSize)); err != nil {
+		return nil, fmt.Errorf("encode thumbnail: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// scaleDown returns the image scaled to fit a square of the size, keeping its
+// aspect ratio. The scaler reads every pixel once, straight from the decoded
+// image, so the work is bounded by the size checked before decoding.
+func scaleDown(img image.Image, size int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w <= size && h <= size {
+		return img
+	}
+
+	longest := max(w, h)
+	dst := image.NewRGBA(image.Rect(0, 0, max(1, w*size/longest), max(1, h*size/longest)))
+	draw.BiLinear.Scale(dst, dst.Bounds(), img, b, draw.Src, nil)
+	return dst
+}
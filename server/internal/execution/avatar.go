@@ -0,0 +1,94 @@
+package execution
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Avatars are the avatars participants can pick from.
+var Avatars = []string{"cat", "dog", "fox", "owl", "panda", "penguin", "rabbit", "tiger", "turtle", "unicorn"}
+
+// Colors are the colors participants can pick from.
+var Colors = []string{"#e53935", "#fb8c00", "#fdd835", "#43a047", "#00897b", "#1e88e5", "#5e35b1", "#d81b60"}
+
+// lobbyParticipant is a participant as shown in the lobby.
+type lobbyParticipant struct {
+	Name   string `json:"name"`
+	Avatar string `json:"avatar"`
+	Color  string `json:"color"`
+}
+
+// look returns the avatar and color in the data of a message, falling back to
+// the given ones if they are not in it. Unknown avatars and colors are an
+// error.
+func look(data map[string]interface{}, avatar, color string) (string, string, error) {
+	if a, ok := data["avatar"].(string); ok {
+		if !slices.Contains(Avatars, a) {
+			log.Error().Str("avatar", a).Msg("Unknown avatar")
+			return "", "", fmt.Errorf("unknown avatar: %s", a)
+		}
+		avatar = a
+	}
+
+	if c, ok := data["color"].(string); ok {
+		if !slices.Contains(Colors, c) {
+			log.Error().Str("color", c).Msg("Unknown color")
+			return "", "", fmt.Errorf("unknown color: %s", c)
+		}
+		color = c
+	}
+
+	return avatar, color, nil
+}
+
+// defaultLook returns the avatar and color given to a participant that did not
+// pick any, so that participants joining one after another look different.
+func (e *Execution) defaultLook() (string, string) {
+	n := len(e.Participants)
+	return Avatars[n%len(Avatars)], Colors[n%len(Colors)]
+}
+
+func (e *Execution) handleSetAvatarMsg(conn Conn, msg Message) error {
+	p, ok := e.getParticipantByConn(conn)
+	if !ok {
+		log.Error().Msg("Only participants can pick an avatar")
+		return fmt.Errorf("only participants can pick an avatar")
+	}
+
+	if e.Phase != PhaseLobby {
+		log.Error().Msg("Avatars can only be changed in the lobby")
+		return fmt.Errorf("avatars can only be changed in the lobby")
+	}
+
+	data, ok := msg.Data.(map[string]interface{})
+	if !ok {
+		log.Error().Msgf("Failed to parse data, expected map[string]string, got %T", msg.Data)
+		return fmt.Errorf("parse data: expected map[string]string, got %T", msg.Data)
+	}
+
+	avatar, color, err := look(data, p.Avatar, p.Color)
+	if err != nil {
+		return err
+	}
+	p.Avatar = avatar
+	p.Color = color
+
+	// Broadcast the new quiz state
+	if err := e.broadcastQuizState(); err != nil {
+		log.Error().Err(err).Msg("Failed to broadcast quiz state")
+		return fmt.Errorf("broadcast quiz state: %w", err)
+	}
+
+	return nil
+}
+
+// getLobbyParticipants returns the participants as shown in the lobby.
+func (e *Execution) getLobbyParticipants() []lobbyParticipant {
+	participants := []lobbyParticipant{}
+	for _, p := range e.Participants {
+		participants = append(participants, lobbyParticipant{Name: p.Name, Avatar: p.Avatar, Color: p.Color})
+	}
+	return participants
+}
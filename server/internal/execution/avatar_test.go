@@ -0,0 +1,199 @@
+package execution
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLook(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       map[string]any
+		wantAvatar string
+		wantColor  string
+		err        string
+	}{
+		{
+			name:       "nothing picked",
+			data:       map[string]any{},
+			wantAvatar: "cat",
+			wantColor:  "#e53935",
+		},
+		{
+			name:       "avatar and color",
+			data:       map[string]any{"avatar": "owl", "color": "#1e88e5"},
+			wantAvatar: "owl",
+			wantColor:  "#1e88e5",
+		},
+		{
+			name:       "only the avatar",
+			data:       map[string]any{"avatar": "owl"},
+			wantAvatar: "owl",
+			wantColor:  "#e53935",
+		},
+		{
+			name:       "only the color",
+			data:       map[string]any{"color": "#1e88e5"},
+			wantAvatar: "cat",
+			wantColor:  "#1e88e5",
+		},
+		{
+			name: "unknown avatar",
+			data: map[string]any{"avatar": "dragon", "color": "#1e88e5"},
+			err:  "unknown avatar: dragon",
+		},
+		{
+			name: "unknown color",
+			data: map[string]any{"avatar": "owl", "color": "#000000"},
+			err:  "unknown color: #000000",
+		},
+		{
+			name: "color in another case",
+			data: map[string]any{"color": "#1E88E5"},
+			err:  "unknown color: #1E88E5",
+		},
+		{
+			name: "markup as avatar",
+			data: map[string]any{"avatar": "<img src=x>"},
+			err:  "unknown avatar",
+		},
+		{
+			name:       "not a string",
+			data:       map[string]any{"avatar": 3, "color": true},
+			wantAvatar: "cat",
+			wantColor:  "#e53935",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			avatar, color, err := look(tt.data, "cat", "#e53935")
+			if tt.err != "" {
+				require.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantAvatar, avatar)
+			require.Equal(t, tt.wantColor, color)
+		})
+	}
+}
+
+func TestSetAvatar(t *testing.T) {
+	tests := []struct {
+		name string
+		// join is the data the participant joins with.
+		join    map[string]any
+		started bool
+		// from is who picks the avatar, the host or p1.
+		from       string
+		data       any
+		err        string
+		wantAvatar string
+		wantColor  string
+	}{
+		{
+			name:       "default look",
+			from:       "p1",
+			data:       map[string]any{},
+			wantAvatar: Avatars[0],
+			wantColor:  Colors[0],
+		},
+		{
+			name:       "picked when joining",
+			join:       map[string]any{"avatar": "owl", "color": "#1e88e5"},
+			from:       "p1",
+			data:       map[string]any{},
+			wantAvatar: "owl",
+			wantColor:  "#1e88e5",
+		},
+		{
+			name:       "picked in the lobby",
+			from:       "p1",
+			data:       map[string]any{"avatar": "owl", "color": "#1e88e5"},
+			wantAvatar: "owl",
+			wantColor:  "#1e88e5",
+		},
+		{
+			name:       "unknown avatar",
+			from:       "p1",
+			data:       map[string]any{"avatar": "dragon", "color": "#1e88e5"},
+			err:        "unknown avatar",
+			wantAvatar: Avatars[0],
+			wantColor:  Colors[0],
+		},
+		{
+			name:       "unknown color",
+			from:       "p1",
+			data:       map[string]any{"avatar": "owl", "color": "red"},
+			err:        "unknown color",
+			wantAvatar: Avatars[0],
+			wantColor:  Colors[0],
+		},
+		{
+			name:       "no data",
+			from:       "p1",
+			data:       "owl",
+			err:        "parse data",
+			wantAvatar: Avatars[0],
+			wantColor:  Colors[0],
+		},
+		{
+			name:       "after the quiz started",
+			started:    true,
+			from:       "p1",
+			data:       map[string]any{"avatar": "owl"},
+			err:        "avatars can only be changed in the lobby",
+			wantAvatar: Avatars[0],
+			wantColor:  Colors[0],
+		},
+		{
+			name:       "not a participant",
+			from:       "host",
+			data:       map[string]any{"avatar": "owl"},
+			err:        "only participants can pick an avatar",
+			wantAvatar: Avatars[0],
+			wantColor:  Colors[0],
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestExecution(Settings{}, testQuestions(1, 2)...)
+			conns := map[string]*testConn{"host": {}, "p1": {}}
+			identities := map[string]Identity{"host": {ID: e.Host.ID}, "p1": {ID: "p1", Name: "p1", IsGuest: true}}
+			require.NoError(t, e.HandleMessage(conns["host"], identities["host"], Message{Type: "Join"}))
+			require.NoError(t, e.HandleMessage(conns["p1"], identities["p1"], Message{Type: "Join", Data: tt.join}))
+			if tt.started {
+				require.NoError(t, e.HandleMessage(conns["host"], identities["host"], Message{Type: "Start"}))
+			}
+
+			err := e.HandleMessage(conns[tt.from], identities[tt.from], Message{Type: "SetAvatar", Data: tt.data})
+			if tt.err != "" {
+				require.ErrorContains(t, err, tt.err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			require.Equal(t, []lobbyParticipant{{Name: "p1", Avatar: tt.wantAvatar, Color: tt.wantColor}}, e.getLobbyParticipants())
+		})
+	}
+
+	t.Run("unknown avatar when joining", func(t *testing.T) {
+		e := newTestExecution(Settings{}, testQuestions(1, 2)...)
+		err := e.HandleMessage(&testConn{}, Identity{ID: "p1", Name: "p1", IsGuest: true}, Message{Type: "Join", Data: map[string]any{"avatar": "dragon"}})
+		require.ErrorContains(t, err, "unknown avatar")
+		require.Empty(t, e.Participants)
+	})
+
+	t.Run("participants joining one after another look different", func(t *testing.T) {
+		e := newTestExecution(Settings{}, testQuestions(1, 2)...)
+		for _, id := range []string{"p1", "p2", "p3"} {
+			require.NoError(t, e.HandleMessage(&testConn{}, Identity{ID: id, Name: id, IsGuest: true}, Message{Type: "Join"}))
+		}
+		require.Equal(t, []lobbyParticipant{
+			{Name: "p1", Avatar: Avatars[0], Color: Colors[0]},
+			{Name: "p2", Avatar: Avatars[1], Color: Colors[1]},
+			{Name: "p3", Avatar: Avatars[2], Color: Colors[2]},
+		}, e.getLobbyParticipants())
+	})
+}
@@ -0,0 +1,148 @@
+package execution
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// clockSyncSamples is how many pings are exchanged with a participant to
+	// measure their latency.
+	clockSyncSamples = 5
+	// maxLatencyCompensation limits how much time is given back to a
+	// participant for their latency, so that a slow or dishonest client
+	// cannot answer long after the question was shown.
+	maxLatencyCompensation = time.Second
+	// latencyJitter is how much a participant's latency may vary from the
+	// measured round trip time.
+	latencyJitter = 100 * time.Millisecond
+	// maxPingAge is how long a ping can be answered.
+	maxPingAge = 10 * time.Second
+)
+
+// clockSync is what a participant is told about their clock once the pings
+// have been exchanged.
+type clockSync struct {
+	Type string `json:"type"`
+	// RoundTrip is the lowest round trip time measured, in milliseconds.
+	RoundTrip int64 `json:"roundTrip"`
+	// Offset is how far the clock of the participant is ahead of the clock of
+	// the server, in milliseconds.
+	Offset int64 `json:"offset"`
+}
+
+// sendPing sends a ping to the participant, to be echoed back in a Pong
+// message with its nonce. The round trip is measured from when the server sent
+// it, so that clients cannot claim a longer one.
+func (p *Participant) sendPing() error {
+	p.pingNonce = uuid.New().String()
+	p.pingSentAt = time.Now()
+	return p.Conn.WriteJSON(struct {
+		Type       string `json:"type"`
+		Nonce      string `json:"nonce"`
+		ServerTime int64  `json:"serverTime"`
+	}{
+		Type:       "ping",
+		Nonce:      p.pingNonce,
+		ServerTime: p.pingSentAt.UnixMilli(),
+	})
+}
+
+// handleClockSyncMsg starts measuring the latency of the participant again,
+// e.g. after their network changed.
+func (e *Execution) handleClockSyncMsg(conn Conn) error {
+	p, ok := e.getParticipantByConn(conn)
+	if !ok {
+		log.Error().Msg("Only participants can sync their clock")
+		return fmt.Errorf("only participants can sync their clock")
+	}
+
+	p.pings = 0
+	p.roundTrip = 0
+	if err := p.sendPing(); err != nil {
+		log.Error().Err(err).Msg("Failed to send ping")
+		return fmt.Errorf("send ping: %w", err)
+	}
+
+	return nil
+}
+
+// handlePongMsg measures the round trip of the ping the participant answers,
+// keeping the lowest one as it is the least disturbed by the network. Another
+// ping is sent until there are enough samples. Pongs that do not answer the
+// outstanding ping are rejected.
+func (e *Execution) handlePongMsg(conn Conn, msg Message) error {
+	p, ok := e.getParticipantByConn(conn)
+	if !ok {
+		log.Error().Msg("Only participants can answer pings")
+		return fmt.Errorf("only participants can answer pings")
+	}
+
+	data, ok := msg.Data.(map[string]interface{})
+	if !ok {
+		log.Error().Msgf("Failed to parse data, expected map[string]string, got %T", msg.Data)
+		return fmt.Errorf("parse data: expected map[string]string, got %T", msg.Data)
+	}
+
+	nonce, _ := data["nonce"].(string)
+	if p.pingNonce == "" || nonce != p.pingNonce {
+		log.Error().Msg("Pong does not answer a ping")
+		return fmt.Errorf("pong does not answer a ping")
+	}
+	clientTime, _ := data["clientTime"].(float64)
+
+	sentAt := p.pingSentAt
+	roundTrip := time.Since(sentAt)
+	p.pingNonce = ""
+	if roundTrip > maxPingAge {
+		log.Error().Dur("roundTrip", roundTrip).Msg("Ping answered too late")
+		return fmt.Errorf("ping answered too late")
+	}
+
+	p.pings++
+	if p.roundTrip == 0 || roundTrip < p.roundTrip {
+		p.roundTrip = roundTrip
+		p.clockOffset = time.UnixMilli(int64(clientTime)).Sub(sentAt.Add(roundTrip / 2))
+	}
+
+	if p.pings < clockSyncSamples {
+		if err := p.sendPing(); err != nil {
+			log.Error().Err(err).Msg("Failed to send ping")
+			return fmt.Errorf("send ping: %w", err)
+		}
+		return nil
+	}
+
+	if err := conn.WriteJSON(clockSync{
+		Type:      "clockSync",
+		RoundTrip: p.roundTrip.Milliseconds(),
+		Offset:    p.clockOffset.Milliseconds(),
+	}); err != nil {
+		log.Error().Err(err).Msg("Failed to send clock sync")
+		return fmt.Errorf("send clock sync: %w", err)
+	}
+
+	return nil
+}
+
+// latencyCompensation returns how much time to give back to the participant
+// for the delay between the question being opened on the server and it being
+// shown to them. The elapsed time the client reports is trusted only within
+// the measured round trip, so clients cannot claim to have answered earlier
+// than the network allows.
+func (e *Execution) latencyCompensation(p *Participant, clientElapsed *time.Duration) time.Duration {
+	if p.roundTrip == 0 {
+		return 0
+	}
+
+	bound := min(p.roundTrip+latencyJitter, maxLatencyCompensation)
+	compensation := min(p.roundTrip, maxLatencyCompensation)
+	if clientElapsed != nil {
+		compensation = time.Since(e.QuestionStartedAt) - *clientElapsed
+	}
+
+	return max(0, min(compensation, bound))
+}
@@ -0,0 +1,140 @@
+package execution
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLatencyCompensation(t *testing.T) {
+	ms := func(n int) *time.Duration {
+		d := time.Duration(n) * time.Millisecond
+		return &d
+	}
+
+	tests := []struct {
+		name          string
+		roundTrip     time.Duration
+		clientElapsed *time.Duration
+		want          time.Duration
+	}{
+		{
+			name:          "not measured",
+			clientElapsed: ms(4800),
+			want:          0,
+		},
+		{
+			name:      "round trip without client time",
+			roundTrip: 200 * time.Millisecond,
+			want:      200 * time.Millisecond,
+		},
+		{
+			name:      "long round trip without client time",
+			roundTrip: 3 * time.Second,
+			want:      maxLatencyCompensation,
+		},
+		{
+			name:          "client time within the round trip",
+			roundTrip:     200 * time.Millisecond,
+			clientElapsed: ms(4850),
+			want:          150 * time.Millisecond,
+		},
+		{
+			name:          "client time beyond the round trip",
+			roundTrip:     200 * time.Millisecond,
+			clientElapsed: ms(4000),
+			want:          200*time.Millisecond + latencyJitter,
+		},
+		{
+			name:          "client time beyond the maximum",
+			roundTrip:     2 * time.Second,
+			clientElapsed: ms(1000),
+			want:          maxLatencyCompensation,
+		},
+		{
+			name:          "client claims more time than passed",
+			roundTrip:     200 * time.Millisecond,
+			clientElapsed: ms(6000),
+			want:          0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestExecution(Settings{})
+			// The question was opened five seconds ago on the server.
+			e.QuestionStartedAt = time.Now().Add(-5 * time.Second)
+			p := &Participant{roundTrip: tt.roundTrip}
+
+			got := e.latencyCompensation(p, tt.clientElapsed)
+			require.InDelta(t, tt.want, got, float64(20*time.Millisecond))
+		})
+	}
+}
+
+func TestHandlePong(t *testing.T) {
+	tests := []struct {
+		name string
+		// answers are how the participant answers each ping: "pong" echoes
+		// its nonce, "replay" sends the previous pong again, "stale" claims
+		// an old ping by its server time and "guess" makes up a nonce.
+		answers   []string
+		err       string
+		pings     int
+		clockSync bool
+	}{
+		{name: "answer to the ping", answers: []string{"pong"}, pings: 1},
+		{name: "all samples", answers: slices.Repeat([]string{"pong"}, clockSyncSamples), pings: clockSyncSamples, clockSync: true},
+		{name: "old server time", answers: []string{"stale"}, err: "pong does not answer a ping"},
+		{name: "made up nonce", answers: []string{"guess"}, err: "pong does not answer a ping"},
+		{name: "answered twice", answers: []string{"pong", "replay"}, err: "pong does not answer a ping", pings: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestExecution(Settings{})
+			conn := &testConn{}
+			e.Participants = append(e.Participants, Participant{ID: "p1", Conn: conn})
+			p := &e.Participants[0]
+			require.NoError(t, p.sendPing())
+
+			var err error
+			var previous Message
+			for _, answer := range tt.answers {
+				data := map[string]any{"clientTime": float64(time.Now().UnixMilli())}
+				switch answer {
+				case "pong":
+					ping := conn.sent[len(conn.sent)-1]
+					data["nonce"] = reflect.ValueOf(ping).FieldByName("Nonce").String()
+				case "stale":
+					data["serverTime"] = float64(time.Now().Add(-5 * time.Second).UnixMilli())
+				case "guess":
+					data["nonce"] = "guess"
+				}
+				msg := Message{Type: "Pong", Data: data}
+				if answer == "replay" {
+					msg = previous
+				}
+				previous = msg
+
+				if err = e.handlePongMsg(conn, msg); err != nil {
+					break
+				}
+			}
+			if tt.err != "" {
+				require.ErrorContains(t, err, tt.err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			require.Equal(t, tt.pings, p.pings)
+			// The round trip is measured from when the ping was sent, not
+			// from what the client claims.
+			require.Less(t, p.roundTrip, time.Second)
+
+			_, isClockSync := conn.sent[len(conn.sent)-1].(clockSync)
+			require.Equal(t, tt.clockSync, isClockSync)
+		})
+	}
+}
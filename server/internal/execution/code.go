@@ -0,0 +1,82 @@
+package execution
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strconv"
+)
+
+const (
+	codeAlphabetEnv = "QUIZZER_CODE_ALPHABET"
+	codeLengthEnv   = "QUIZZER_CODE_LENGTH"
+)
+
+// CodeConfig configures the codes participants use to join an execution.
+type CodeConfig struct {
+	Alphabet string
+	Length   int
+}
+
+func DefaultCodeConfig() CodeConfig {
+	return CodeConfig{Alphabet: "0123456789", Length: 6}
+}
+
+// CodeConfigFromEnv returns the default code config, overridden by the
+// QUIZZER_CODE_ALPHABET and QUIZZER_CODE_LENGTH environment variables if set.
+func CodeConfigFromEnv() (CodeConfig, error) {
+	config := DefaultCodeConfig()
+	if alphabet := os.Getenv(codeAlphabetEnv); alphabet != "" {
+		config.Alphabet = alphabet
+	}
+
+	if length := os.Getenv(codeLengthEnv); length != "" {
+		l, err := strconv.Atoi(length)
+		if err != nil {
+			return CodeConfig{}, fmt.Errorf("parse %s: %w", codeLengthEnv, err)
+		}
+		config.Length = l
+	}
+
+	return config, config.Validate()
+}
+
+func (c CodeConfig) Validate() error {
+	seen := map[rune]bool{}
+	for _, r := range c.Alphabet {
+		if seen[r] {
+			return fmt.Errorf("code alphabet contains %q more than once", r)
+		}
+		seen[r] = true
+	}
+
+	if len(seen) < 2 {
+		return errors.New("code alphabet must contain at least two characters")
+	}
+
+	if c.Length < 4 {
+		return errors.New("code length must be at least 4")
+	}
+
+	return nil
+}
+
+// generate returns a random code using a cryptographically secure source, so
+// that live codes cannot be predicted from earlier ones.
+func (c CodeConfig) generate() (string, error) {
+	alphabet := []rune(c.Alphabet)
+	max := big.NewInt(int64(len(alphabet)))
+
+	code := make([]rune, c.Length)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		code[i] = alphabet[n.Int64()]
+	}
+
+	return string(code), nil
+}
@@ -0,0 +1,59 @@
+package execution
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodeConfig(t *testing.T) {
+	t.Run("validate", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			config CodeConfig
+			valid  bool
+		}{
+			{name: "default", config: DefaultCodeConfig(), valid: true},
+			{name: "letters", config: CodeConfig{Alphabet: "ABCDEFGHJKLMNPQRSTUVWXYZ", Length: 5}, valid: true},
+			{name: "repeated character", config: CodeConfig{Alphabet: "0120", Length: 6}},
+			{name: "single character", config: CodeConfig{Alphabet: "0", Length: 6}},
+			{name: "empty alphabet", config: CodeConfig{Length: 6}},
+			{name: "too short", config: CodeConfig{Alphabet: "0123456789", Length: 3}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				err := tt.config.Validate()
+				if tt.valid {
+					require.NoError(t, err)
+				} else {
+					require.Error(t, err)
+				}
+			})
+		}
+	})
+
+	t.Run("generate", func(t *testing.T) {
+		tests := []CodeConfig{
+			DefaultCodeConfig(),
+			{Alphabet: "AB", Length: 4},
+			{Alphabet: "åäö", Length: 8},
+		}
+		for _, config := range tests {
+			t.Run(config.Alphabet, func(t *testing.T) {
+				seen := map[rune]bool{}
+				for range 100 {
+					code, err := config.generate()
+					require.NoError(t, err)
+					require.Len(t, []rune(code), config.Length)
+					for _, r := range code {
+						require.True(t, strings.ContainsRune(config.Alphabet, r), "unexpected %q in %s", r, code)
+						seen[r] = true
+					}
+				}
+				// Every character shows up sooner or later.
+				require.Len(t, seen, len([]rune(config.Alphabet)))
+			})
+		}
+	})
+}
@@ -0,0 +1,174 @@
+package execution
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/rs/zerolog/log"
+	"github.com/william-joh/quizzer/server/internal/quizzer"
+)
+
+// CoHost is a logged in user that the host has allowed to control the
+// execution, e.g. a teaching assistant.
+type CoHost struct {
+	Conn Conn
+	ID   string `json:"userId"`
+	Name string `json:"name"`
+}
+
+// canControl reports whether the connection belongs to the host or a co-host.
+func (e *Execution) canControl(conn Conn) bool {
+	if conn == nil {
+		return false
+	}
+
+	if e.HostConn == conn {
+		return true
+	}
+
+	return slices.ContainsFunc(e.CoHosts, func(c CoHost) bool {
+		return c.Conn == conn
+	})
+}
+
+// isJoined reports whether the connection has already joined the execution in
+// any role.
+func (e *Execution) isJoined(conn Conn) bool {
+	if _, ok := e.getParticipantByConn(conn); ok {
+		return true
+	}
+	return e.canControl(conn) || slices.Contains(e.Spectators, conn)
+}
+
+func (e *Execution) handleGrantCoHostMsg(conn Conn, msg Message) error {
+	if e.HostConn != conn {
+		log.Error().Msg("Only the host can grant co-host rights")
+		return fmt.Errorf("%w: only the host can grant co-host rights", ErrForbidden)
+	}
+
+	userID, err := userIDFromMsg(msg)
+	if err != nil {
+		return err
+	}
+
+	if userID == e.Host.ID || slices.Contains(e.CoHostIDs, userID) {
+		log.Error().Str("userId", userID).Msg("User is already a host")
+		return fmt.Errorf("user %s is already a host", userID)
+	}
+
+	// A connected participant stops taking part in the quiz and starts
+	// controlling it instead. Once the quiz has started, they keep their
+	// place on the leaderboard like participants that left. Guests have no
+	// account to grant rights to.
+	if i := slices.IndexFunc(e.Participants, func(p Participant) bool { return p.ID == userID }); i >= 0 {
+		p := e.Participants[i]
+		if p.IsGuest {
+			log.Error().Str("userId", userID).Msg("Guests cannot be co-hosts")
+			return fmt.Errorf("guests cannot be co-hosts")
+		}
+
+		e.Participants = slices.Delete(e.Participants, i, i+1)
+		e.CoHosts = append(e.CoHosts, CoHost{Conn: p.Conn, ID: p.ID, Name: p.Name})
+		if e.Phase != PhaseLobby {
+			p.Conn = nil
+			e.departed = append(e.departed, p)
+		}
+	}
+	e.CoHostIDs = append(e.CoHostIDs, userID)
+
+	// Broadcast the new quiz state
+	if err := e.broadcastQuizState(); err != nil {
+		log.Error().Err(err).Msg("Failed to broadcast quiz state")
+		return fmt.Errorf("broadcast quiz state: %w", err)
+	}
+
+	return nil
+}
+
+func (e *Execution) handleRevokeCoHostMsg(conn Conn, msg Message) error {
+	if e.HostConn != conn {
+		log.Error().Msg("Only the host can revoke co-host rights")
+		return fmt.Errorf("%w: only the host can revoke co-host rights", ErrForbidden)
+	}
+
+	userID, err := userIDFromMsg(msg)
+	if err != nil {
+		return err
+	}
+
+	if !slices.Contains(e.CoHostIDs, userID) {
+		log.Error().Str("userId", userID).Msg("User is not a co-host")
+		return fmt.Errorf("user %s is not a co-host", userID)
+	}
+
+	e.CoHostIDs = slices.DeleteFunc(e.CoHostIDs, func(id string) bool { return id == userID })
+
+	// A connected co-host keeps following the quiz as a spectator.
+	e.CoHosts = slices.DeleteFunc(e.CoHosts, func(c CoHost) bool {
+		if c.ID == userID {
+			e.Spectators = append(e.Spectators, c.Conn)
+			return true
+		}
+		return false
+	})
+
+	// Broadcast the new quiz state
+	if err := e.broadcastQuizState(); err != nil {
+		log.Error().Err(err).Msg("Failed to broadcast quiz state")
+		return fmt.Errorf("broadcast quiz state: %w", err)
+	}
+
+	return nil
+}
+
+// handleHandOverMsg makes a connected co-host the owner of the execution. The
+// previous host becomes a co-host, and may leave without ending the quiz.
+func (e *Execution) handleHandOverMsg(conn Conn, msg Message) error {
+	if e.HostConn != conn {
+		log.Error().Msg("Only the host can hand over the quiz")
+		return fmt.Errorf("%w: only the host can hand over the quiz", ErrForbidden)
+	}
+
+	userID, err := userIDFromMsg(msg)
+	if err != nil {
+		return err
+	}
+
+	i := slices.IndexFunc(e.CoHosts, func(c CoHost) bool { return c.ID == userID })
+	if i < 0 {
+		log.Error().Str("userId", userID).Msg("User is not a connected co-host")
+		return fmt.Errorf("user %s is not a connected co-host", userID)
+	}
+
+	newHost := e.CoHosts[i]
+	e.CoHosts[i] = CoHost{Conn: e.HostConn, ID: e.Host.ID, Name: e.Host.Username}
+	e.CoHostIDs = slices.DeleteFunc(e.CoHostIDs, func(id string) bool { return id == newHost.ID })
+	e.CoHostIDs = append(e.CoHostIDs, e.Host.ID)
+
+	e.Host = quizzer.User{ID: newHost.ID, Username: newHost.Name}
+	e.HostConn = newHost.Conn
+
+	// Broadcast the new quiz state
+	if err := e.broadcastQuizState(); err != nil {
+		log.Error().Err(err).Msg("Failed to broadcast quiz state")
+		return fmt.Errorf("broadcast quiz state: %w", err)
+	}
+
+	return nil
+}
+
+func userIDFromMsg(msg Message) (string, error) {
+	data, ok := msg.Data.(map[string]interface{})
+	if !ok {
+		log.Error().Msgf("Failed to parse data, expected map[string]string, got %T", msg.Data)
+		return "", fmt.Errorf("parse data: expected map[string]string, got %T", msg.Data)
+	}
+
+	userID, ok := data["userId"].(string)
+	if !ok || userID == "" {
+		log.Error().Msg("User ID not provided")
+		return "", fmt.Errorf("user ID not provided")
+	}
+
+	return userID, nil
+}
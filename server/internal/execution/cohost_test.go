@@ -0,0 +1,186 @@
+package execution
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/william-joh/quizzer/server/internal/quizzer"
+)
+
+func TestCoHosts(t *testing.T) {
+	question := quizzer.Question{ID: "q1", Question: "q1", Options: []quizzer.AnswerOption{
+		{ID: "o1", Text: "a", IsCorrect: true},
+		{ID: "o2", Text: "b"},
+	}}
+
+	// join returns an execution joined by the host, the co-host c1, the
+	// participant u1 and the guest g1, with their connections.
+	join := func(t *testing.T) (*Execution, map[string]*testConn) {
+		e := newTestExecution(Settings{}, question)
+		e.CoHostIDs = []string{"c1"}
+		conns := map[string]*testConn{"host": {}, "co-host": {}, "participant": {}, "guest": {}}
+		require.NoError(t, e.HandleMessage(conns["host"], Identity{ID: e.Host.ID, Name: "host"}, Message{Type: "Join"}))
+		require.NoError(t, e.HandleMessage(conns["co-host"], Identity{ID: "c1", Name: "c1"}, Message{Type: "Join"}))
+		require.NoError(t, e.HandleMessage(conns["participant"], Identity{ID: "u1", Name: "u1"}, Message{Type: "Join"}))
+		require.NoError(t, e.HandleMessage(conns["guest"], Identity{ID: "g1", Name: "g1", IsGuest: true}, Message{Type: "Join"}))
+		return e, conns
+	}
+
+	tests := []struct {
+		name string
+		// started plays the question before the message is sent, with the
+		// participant answering it correctly.
+		started bool
+		// from is who sends the message: the host, the co-host or the
+		// participant.
+		from    string
+		msgType string
+		userID  string
+		err     string
+		// want holds the host, the co-hosts, the participants and the number
+		// of spectators after the message, and the scores on the leaderboard.
+		wantHost         string
+		wantCoHosts      []string
+		wantParticipants []string
+		wantSpectators   int
+		wantScores       map[string]int
+	}{
+		{
+			name:             "grant",
+			from:             "host",
+			msgType:          "GrantCoHost",
+			userID:           "u1",
+			wantHost:         "host-id",
+			wantCoHosts:      []string{"c1", "u1"},
+			wantParticipants: []string{"g1"},
+			wantScores:       map[string]int{"g1": 0},
+		},
+		{
+			name:             "grant after answering",
+			started:          true,
+			from:             "host",
+			msgType:          "GrantCoHost",
+			userID:           "u1",
+			wantHost:         "host-id",
+			wantCoHosts:      []string{"c1", "u1"},
+			wantParticipants: []string{"g1"},
+			wantScores:       map[string]int{"u1": 1, "g1": 0},
+		},
+		{
+			name:             "grant to a guest",
+			from:             "host",
+			msgType:          "GrantCoHost",
+			userID:           "g1",
+			err:              "guests cannot be co-hosts",
+			wantHost:         "host-id",
+			wantCoHosts:      []string{"c1"},
+			wantParticipants: []string{"u1", "g1"},
+			wantScores:       map[string]int{"u1": 0, "g1": 0},
+		},
+		{
+			name:             "grant to a co-host",
+			from:             "host",
+			msgType:          "GrantCoHost",
+			userID:           "c1",
+			err:              "user c1 is already a host",
+			wantHost:         "host-id",
+			wantCoHosts:      []string{"c1"},
+			wantParticipants: []string{"u1", "g1"},
+			wantScores:       map[string]int{"u1": 0, "g1": 0},
+		},
+		{
+			name:             "revoke",
+			from:             "host",
+			msgType:          "RevokeCoHost",
+			userID:           "c1",
+			wantHost:         "host-id",
+			wantCoHosts:      []string{},
+			wantParticipants: []string{"u1", "g1"},
+			wantSpectators:   1,
+			wantScores:       map[string]int{"u1": 0, "g1": 0},
+		},
+		{
+			name:             "revoke from a participant",
+			from:             "host",
+			msgType:          "RevokeCoHost",
+			userID:           "u1",
+			err:              "user u1 is not a co-host",
+			wantHost:         "host-id",
+			wantCoHosts:      []string{"c1"},
+			wantParticipants: []string{"u1", "g1"},
+			wantScores:       map[string]int{"u1": 0, "g1": 0},
+		},
+		{
+			name:             "hand over",
+			from:             "host",
+			msgType:          "HandOver",
+			userID:           "c1",
+			wantHost:         "c1",
+			wantCoHosts:      []string{"host-id"},
+			wantParticipants: []string{"u1", "g1"},
+			wantScores:       map[string]int{"u1": 0, "g1": 0},
+		},
+		{
+			name:             "hand over to a participant",
+			from:             "host",
+			msgType:          "HandOver",
+			userID:           "u1",
+			err:              "user u1 is not a connected co-host",
+			wantHost:         "host-id",
+			wantCoHosts:      []string{"c1"},
+			wantParticipants: []string{"u1", "g1"},
+			wantScores:       map[string]int{"u1": 0, "g1": 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, conns := join(t)
+			if tt.started {
+				require.NoError(t, e.HandleMessage(conns["host"], Identity{ID: e.Host.ID}, Message{Type: "Start"}))
+				require.NoError(t, e.HandleMessage(conns["participant"], Identity{ID: "u1"}, Message{Type: "AnswerQuestion", Data: map[string]any{"optionId": "o1"}}))
+				require.NoError(t, e.HandleMessage(conns["host"], Identity{ID: e.Host.ID}, Message{Type: "FinishQuestion"}))
+			}
+
+			err := e.HandleMessage(conns[tt.from], Identity{}, Message{Type: tt.msgType, Data: map[string]any{"userId": tt.userID}})
+			if tt.err != "" {
+				require.ErrorContains(t, err, tt.err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			require.Equal(t, tt.wantHost, e.Host.ID)
+			coHosts := []string{}
+			for _, c := range e.CoHosts {
+				coHosts = append(coHosts, c.ID)
+			}
+			require.Equal(t, tt.wantCoHosts, coHosts)
+			require.ElementsMatch(t, tt.wantCoHosts, e.CoHostIDs)
+			participants := []string{}
+			for _, p := range e.Participants {
+				participants = append(participants, p.ID)
+			}
+			require.Equal(t, tt.wantParticipants, participants)
+			require.Len(t, e.Spectators, tt.wantSpectators)
+
+			scores := map[string]int{}
+			for _, r := range e.getResults() {
+				scores[r.userID] = r.Score
+			}
+			require.Equal(t, tt.wantScores, scores)
+		})
+	}
+	for _, msgType := range []string{"GrantCoHost", "RevokeCoHost", "HandOver"} {
+		for _, from := range []string{"co-host", "participant"} {
+			t.Run(msgType+" by the "+from, func(t *testing.T) {
+				e, conns := join(t)
+
+				err := e.HandleMessage(conns[from], Identity{}, Message{Type: msgType, Data: map[string]any{"userId": "c1"}})
+				require.ErrorIs(t, err, ErrForbidden)
+				require.Equal(t, "host-id", e.Host.ID)
+				require.Equal(t, []string{"c1"}, e.CoHostIDs)
+				require.Len(t, e.CoHosts, 1)
+				require.Empty(t, e.Spectators)
+			})
+		}
+	}
+}
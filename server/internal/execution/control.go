@@ -0,0 +1,131 @@
+package execution
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Action is something the host or a co-host can do to a running execution
+// without being connected to it.
+type Action string
+
+const (
+	ActionStart  Action = "start"
+	ActionNext   Action = "next"
+	ActionFinish Action = "finish"
+	ActionPause  Action = "pause"
+	ActionResume Action = "resume"
+	ActionSkip   Action = "skip"
+	ActionReopen Action = "reopen"
+	// ActionEnd may only be done by the host.
+	ActionEnd Action = "end"
+)
+
+// ErrNotFound is returned when looking up an execution that is not running.
+var ErrNotFound = errors.New("execution not found")
+
+// ErrForbidden is returned when a user tries to control an execution they are
+// not the host or a co-host of.
+var ErrForbidden = errors.New("not allowed to control the execution")
+
+// ErrUnknownAction is returned when controlling an execution with an action
+// that does not exist.
+var ErrUnknownAction = errors.New("unknown action")
+
+// Status is the state of an execution as seen by its host.
+type Status struct {
+	Code            string      `json:"code"`
+	QuizID          string      `json:"quizId"`
+	QuizTitle       string      `json:"quizTitle"`
+	HostName        string      `json:"hostName"`
+	IsOwner         bool        `json:"isOwner"`
+	Phase           Phase       `json:"phase"`
+	CurrentQuestion int         `json:"currentQuestion"`
+	TotalQuestions  int         `json:"totalQuestions"`
+	NrParticipants  int         `json:"nrParticipants"`
+	CreatedAt       time.Time   `json:"createdAt"`
+	State           interface{} `json:"state"`
+}
+
+// isController reports whether the user is the host or a co-host.
+func (e *Execution) isController(userID string) bool {
+	return userID == e.Host.ID || slices.Contains(e.CoHostIDs, userID)
+}
+
+// Control does the action on behalf of the user, in the same way as the
+// corresponding message from a connected host.
+func (e *Execution) Control(userID string, action Action) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if !e.isController(userID) || (action == ActionEnd && userID != e.Host.ID) {
+		log.Error().Str("userId", userID).Str("action", string(action)).Msg("User may not control the quiz")
+		return ErrForbidden
+	}
+
+	switch action {
+	case ActionStart:
+		return e.start()
+	case ActionNext:
+		return e.next()
+	case ActionFinish:
+		return e.finish()
+	case ActionPause:
+		return e.pause()
+	case ActionResume:
+		return e.resume()
+	case ActionSkip:
+		return e.skip()
+	case ActionReopen:
+		return e.reopen()
+	case ActionEnd:
+		return e.end()
+	default:
+		log.Error().Str("action", string(action)).Msg("Unknown action")
+		return fmt.Errorf("%w: %s", ErrUnknownAction, action)
+	}
+}
+
+// Status returns the state of the execution, if the user is its host or a
+// co-host.
+func (e *Execution) Status(userID string) (Status, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if !e.isController(userID) {
+		return Status{}, ErrForbidden
+	}
+
+	state, err := e.getHostPayload()
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get host payload")
+		return Status{}, fmt.Errorf("get host payload: %w", err)
+	}
+
+	return Status{
+		Code:            e.Code,
+		QuizID:          e.Quiz.ID,
+		QuizTitle:       e.Quiz.Title,
+		HostName:        e.Host.Username,
+		IsOwner:         userID == e.Host.ID,
+		Phase:           e.Phase,
+		CurrentQuestion: e.CurrentQuestion,
+		TotalQuestions:  len(e.Questions),
+		NrParticipants:  len(e.Participants),
+		CreatedAt:       e.CreatedAt,
+		State:           state,
+	}, nil
+}
+
+// CanControl reports whether the user is the host or a co-host of the
+// execution.
+func (e *Execution) CanControl(userID string) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.isController(userID)
+}
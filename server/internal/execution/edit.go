@@ -0,0 +1,189 @@
+package execution
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"github.com/william-joh/quizzer/server/internal/quizzer"
+)
+
+// ErrQuestionNotFound is returned when editing a question that is not part of
+// the execution.
+var ErrQuestionNotFound = errors.New("question not found")
+
+// ErrInvalidEdit is returned when a fix to a question would leave it in a state
+// that cannot be answered.
+var ErrInvalidEdit = errors.New("invalid edit")
+
+// QuestionEdit is a fix to a question of a running execution, e.g. a typo or
+// the wrong answer marked as correct.
+type QuestionEdit struct {
+	Question string `json:"question"`
+	// Options replace the answer options of the question. Options without an
+	// ID are added and options that are left out are removed.
+	Options []quizzer.AnswerOption `json:"options"`
+	// Regrade scores the answers that were already given again, against the
+	// fixed question. Otherwise the answers to an open question are cleared
+	// so that participants answer again, and the answers to a finished
+	// question keep their scores.
+	Regrade bool `json:"regrade"`
+	// Save also saves the fix to the quiz, before the game is changed.
+	Save bool `json:"save"`
+}
+
+// EditQuestion fixes the question on behalf of the host or a co-host. Only the
+// current question, the one that just finished and upcoming questions can be
+// edited. If the edit is to be saved, save is called with the fixed question
+// and the game is only changed if it succeeds. Saving does not hold up the
+// game, so the edit is rejected if the question changed in the meantime. The
+// fixed question is returned.
+func (e *Execution) EditQuestion(userID, questionID string, edit QuestionEdit, save func(quizzer.Question) error) (quizzer.Question, error) {
+	e.mu.Lock()
+	i, err := e.editableQuestion(userID, questionID)
+	if err != nil {
+		e.mu.Unlock()
+		return quizzer.Question{}, err
+	}
+	original := e.Questions[i]
+	e.mu.Unlock()
+
+	q, err := fixQuestion(original, edit)
+	if err != nil {
+		return quizzer.Question{}, err
+	}
+
+	if edit.Save {
+		if err := save(q); err != nil {
+			log.Error().Err(err).Str("questionId", questionID).Msg("Failed to save question")
+			return quizzer.Question{}, fmt.Errorf("save question: %w", err)
+		}
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	i, err = e.editableQuestion(userID, questionID)
+	if err != nil {
+		return quizzer.Question{}, err
+	}
+	if !reflect.DeepEqual(e.Questions[i], original) {
+		log.Error().Str("questionId", questionID).Msg("Question changed while saving")
+		return quizzer.Question{}, fmt.Errorf("question changed while saving")
+	}
+
+	if !edit.Save {
+		// Options that were added without saving them to the quiz cannot
+		// be referred to from the game history.
+		for _, o := range q.Options {
+			if _, ok := original.Option(o.ID); !ok {
+				e.unsavedOptions[o.ID] = true
+			}
+		}
+	}
+	e.Questions[i] = q
+
+	finished := i < e.CurrentQuestion
+	for _, p := range e.allParticipants() {
+		// Answers to options that were removed cannot be kept.
+		if answer, ok := p.Answers[q.ID]; ok {
+			if _, ok := q.Option(answer); !ok || (!finished && !edit.Regrade) {
+				delete(p.Answers, q.ID)
+				delete(p.answerTimeLeft, q.ID)
+				delete(p.answeredAt, q.ID)
+			}
+		}
+	}
+
+	if finished && edit.Regrade && !e.SkippedQuestions[q.ID] {
+		e.regrade(i)
+	}
+
+	// Broadcast the new quiz state
+	if err := e.broadcastQuizState(); err != nil {
+		log.Error().Err(err).Msg("Failed to broadcast quiz state")
+		return quizzer.Question{}, fmt.Errorf("broadcast quiz state: %w", err)
+	}
+
+	return q, nil
+}
+
+// editableQuestion returns the index of the question, if the user may edit it
+// now.
+func (e *Execution) editableQuestion(userID, questionID string) (int, error) {
+	if !e.isController(userID) {
+		return 0, ErrForbidden
+	}
+
+	i := slices.IndexFunc(e.Questions, func(q quizzer.Question) bool { return q.ID == questionID })
+	if i < 0 || e.Tiebreaks[questionID] {
+		return 0, ErrQuestionNotFound
+	}
+
+	// The question that just finished is still shown while its answer is
+	// revealed or its results are on screen.
+	finished := i < e.CurrentQuestion
+	shown := e.Phase == PhaseReveal || e.Phase == PhaseResults || e.Phase == PhaseIntermission || e.Phase == PhaseFinished
+	if finished && (i != e.CurrentQuestion-1 || !shown) {
+		log.Error().Str("questionId", questionID).Msg("Question can no longer be edited")
+		return 0, fmt.Errorf("question can no longer be edited")
+	}
+
+	return i, nil
+}
+
+// fixQuestion returns the question with the edit applied.
+func fixQuestion(q quizzer.Question, edit QuestionEdit) (quizzer.Question, error) {
+	if strings.TrimSpace(edit.Question) == "" {
+		log.Error().Msg("Question is empty")
+		return quizzer.Question{}, fmt.Errorf("%w: question cannot be empty", ErrInvalidEdit)
+	}
+
+	options := []quizzer.AnswerOption{}
+	for i, o := range edit.Options {
+		if o.ID == "" {
+			o.ID = uuid.New().String()
+		} else if _, ok := q.Option(o.ID); !ok {
+			log.Error().Str("optionId", o.ID).Msg("Option not found")
+			return quizzer.Question{}, fmt.Errorf("%w: option %s not found", ErrInvalidEdit, o.ID)
+		} else if slices.ContainsFunc(edit.Options[:i], func(other quizzer.AnswerOption) bool { return other.ID == o.ID }) {
+			log.Error().Str("optionId", o.ID).Msg("Duplicate option")
+			return quizzer.Question{}, fmt.Errorf("%w: duplicate option %s", ErrInvalidEdit, o.ID)
+		}
+		o.QuestionID = q.ID
+		o.Index = i
+		options = append(options, o)
+	}
+
+	q.Question = edit.Question
+	q.Options = options
+	if err := q.Validate(); err != nil {
+		log.Error().Err(err).Msg("Invalid question")
+		return quizzer.Question{}, fmt.Errorf("%w: %w", ErrInvalidEdit, err)
+	}
+
+	return q, nil
+}
+
+// regrade scores the finished question at the given index again, and redoes
+// its eliminations.
+func (e *Execution) regrade(question int) {
+	q := e.Questions[question]
+	for _, p := range e.allParticipants() {
+		p.Scores[q.ID] = e.score(q, p)
+	}
+
+	if !e.Settings.Elimination {
+		return
+	}
+
+	e.restore(question)
+	e.eliminate(question)
+	if e.Phase == PhaseResults || e.Phase == PhaseIntermission || e.Phase == PhaseFinished {
+		e.showResults()
+	}
+}
@@ -0,0 +1,179 @@
+package execution
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/william-joh/quizzer/server/internal/quizzer"
+)
+
+func TestEditQuestion(t *testing.T) {
+	question := quizzer.Question{ID: "q1", Question: "q1", Options: []quizzer.AnswerOption{
+		{ID: "o1", Text: "a", IsCorrect: true},
+		{ID: "o2", Text: "b"},
+	}}
+	// swapped marks the second option as correct instead of the first.
+	swapped := QuestionEdit{Question: "q1", Options: []quizzer.AnswerOption{
+		{ID: "o1", Text: "a"},
+		{ID: "o2", Text: "b", IsCorrect: true},
+	}}
+	p1 := Identity{ID: "p1", Name: "p1", IsGuest: true}
+	p2 := Identity{ID: "p2", Name: "p2", IsGuest: true}
+
+	tests := []struct {
+		name string
+		// control is the message the host sends once both participants
+		// answered, if any.
+		control string
+		userID  string
+		edit    QuestionEdit
+		// saveErr is returned when the edit is saved. With change, the
+		// question is edited by someone else while it is being saved.
+		saveErr error
+		change  bool
+		err     error
+		errMsg  string
+		answers map[string]string
+		scores  map[string]int
+	}{
+		{
+			name:    "regrade finished question",
+			control: "FinishQuestion",
+			userID:  "host-id",
+			edit:    QuestionEdit{Question: swapped.Question, Options: swapped.Options, Regrade: true},
+			answers: map[string]string{"p1": "o1", "p2": "o2"},
+			scores:  map[string]int{"p1": 0, "p2": 1},
+		},
+		{
+			name:    "finished question keeps its scores",
+			control: "FinishQuestion",
+			userID:  "host-id",
+			edit:    swapped,
+			answers: map[string]string{"p1": "o1", "p2": "o2"},
+			scores:  map[string]int{"p1": 1, "p2": 0},
+		},
+		{
+			name:    "skipped question is not scored",
+			control: "SkipQuestion",
+			userID:  "host-id",
+			edit:    QuestionEdit{Question: swapped.Question, Options: swapped.Options, Regrade: true},
+			answers: map[string]string{"p1": "o1", "p2": "o2"},
+			scores:  map[string]int{},
+		},
+		{
+			name:    "open question is answered again",
+			userID:  "host-id",
+			edit:    swapped,
+			answers: map[string]string{},
+			scores:  map[string]int{},
+		},
+		{
+			name:    "open question keeps its answers when regraded",
+			userID:  "host-id",
+			edit:    QuestionEdit{Question: swapped.Question, Options: swapped.Options, Regrade: true},
+			answers: map[string]string{"p1": "o1", "p2": "o2"},
+			scores:  map[string]int{},
+		},
+		{
+			name:    "answers to removed options are dropped",
+			control: "FinishQuestion",
+			userID:  "host-id",
+			edit: QuestionEdit{Question: "q1", Options: []quizzer.AnswerOption{
+				{Text: "c", IsCorrect: true},
+				{ID: "o2", Text: "b"},
+			}, Regrade: true},
+			answers: map[string]string{"p2": "o2"},
+			scores:  map[string]int{"p1": 0, "p2": 0},
+		},
+		{
+			name:    "no correct option",
+			control: "FinishQuestion",
+			userID:  "host-id",
+			edit: QuestionEdit{Question: "q1", Options: []quizzer.AnswerOption{
+				{ID: "o1", Text: "a"},
+				{ID: "o2", Text: "b"},
+			}, Regrade: true},
+			err:     ErrInvalidEdit,
+			answers: map[string]string{"p1": "o1", "p2": "o2"},
+			scores:  map[string]int{"p1": 1, "p2": 0},
+		},
+		{
+			name:    "not a host",
+			control: "FinishQuestion",
+			userID:  "p1",
+			edit:    QuestionEdit{Question: swapped.Question, Options: swapped.Options, Regrade: true},
+			err:     ErrForbidden,
+			answers: map[string]string{"p1": "o1", "p2": "o2"},
+			scores:  map[string]int{"p1": 1, "p2": 0},
+		},
+		{
+			name:    "save fails",
+			control: "FinishQuestion",
+			userID:  "host-id",
+			edit:    QuestionEdit{Question: swapped.Question, Options: swapped.Options, Regrade: true, Save: true},
+			saveErr: errors.New("db down"),
+			errMsg:  "save question: db down",
+			answers: map[string]string{"p1": "o1", "p2": "o2"},
+			scores:  map[string]int{"p1": 1, "p2": 0},
+		},
+		{
+			name:    "question changed while saving",
+			control: "FinishQuestion",
+			userID:  "host-id",
+			edit:    QuestionEdit{Question: swapped.Question, Options: swapped.Options, Regrade: true, Save: true},
+			change:  true,
+			errMsg:  "question changed while saving",
+			answers: map[string]string{"p1": "o1", "p2": "o2"},
+			scores:  map[string]int{"p1": 1, "p2": 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestExecution(Settings{}, question)
+			host, c1, c2 := &testConn{}, &testConn{}, &testConn{}
+			require.NoError(t, e.HandleMessage(host, Identity{ID: e.Host.ID}, Message{Type: "Join"}))
+			require.NoError(t, e.HandleMessage(c1, p1, Message{Type: "Join"}))
+			require.NoError(t, e.HandleMessage(c2, p2, Message{Type: "Join"}))
+			require.NoError(t, e.HandleMessage(host, Identity{ID: e.Host.ID}, Message{Type: "Start"}))
+			require.NoError(t, e.HandleMessage(c1, p1, Message{Type: "AnswerQuestion", Data: map[string]any{"optionId": "o1"}}))
+			require.NoError(t, e.HandleMessage(c2, p2, Message{Type: "AnswerQuestion", Data: map[string]any{"optionId": "o2"}}))
+			if tt.control != "" {
+				require.NoError(t, e.HandleMessage(host, Identity{ID: e.Host.ID}, Message{Type: tt.control}))
+			}
+
+			save := func(q quizzer.Question) error {
+				// The game is not locked while saving.
+				require.True(t, e.mu.TryLock())
+				e.mu.Unlock()
+
+				if tt.change {
+					_, err := e.EditQuestion("host-id", q.ID, QuestionEdit{Question: "changed", Options: question.Options}, nil)
+					require.NoError(t, err)
+				}
+				return tt.saveErr
+			}
+			_, err := e.EditQuestion(tt.userID, "q1", tt.edit, save)
+			switch {
+			case tt.err != nil:
+				require.ErrorIs(t, err, tt.err)
+			case tt.errMsg != "":
+				require.EqualError(t, err, tt.errMsg)
+			default:
+				require.NoError(t, err)
+			}
+
+			answers, scores := map[string]string{}, map[string]int{}
+			for _, p := range e.allParticipants() {
+				if answer, ok := p.Answers["q1"]; ok {
+					answers[p.ID] = answer
+				}
+				if score, ok := p.Scores["q1"]; ok {
+					scores[p.ID] = score
+				}
+			}
+			require.Equal(t, tt.answers, answers)
+			require.Equal(t, tt.scores, scores)
+		})
+	}
+}
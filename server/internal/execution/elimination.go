@@ -0,0 +1,97 @@
+package execution
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+
+	"github.com/william-joh/quizzer/server/internal/quizzer"
+)
+
+// PhaseFinished shows the final results once there is nothing left to show, or
+// once no more than the configured number of survivors remain in an
+// elimination execution. No more questions can be started from it.
+const PhaseFinished Phase = "finished"
+
+// survivorTarget returns how many participants remain when an elimination
+// execution is over.
+func (e *Execution) survivorTarget() int {
+	if e.Settings.Survivors == 0 {
+		return 1
+	}
+	return int(e.Settings.Survivors)
+}
+
+// activeParticipants returns the participants that have not been eliminated.
+func (e *Execution) activeParticipants() []*Participant {
+	active := []*Participant{}
+	for i := range e.Participants {
+		if !e.Participants[i].Eliminated {
+			active = append(active, &e.Participants[i])
+		}
+	}
+	return active
+}
+
+// eliminate eliminates the participants that answered the question at the
+// given index wrong or not at all. If all remaining participants would be
+// eliminated, nobody is, so that the execution cannot end without a winner.
+func (e *Execution) eliminate(question int) {
+	q := e.Questions[question]
+	failed := []*Participant{}
+	active := e.activeParticipants()
+	for _, p := range active {
+		if answer, ok := p.Answers[q.ID]; !ok || !q.IsCorrect(answer) {
+			failed = append(failed, p)
+		}
+	}
+
+	if len(failed) == len(active) {
+		return
+	}
+
+	for _, p := range failed {
+		p.Eliminated = true
+		p.EliminatedIn = question + 1
+	}
+}
+
+// isOver returns whether few enough participants remain to end an elimination
+// execution. It is also over when everyone has left, as there is nobody to
+// break a tie between.
+func (e *Execution) isOver() bool {
+	return len(e.activeParticipants()) <= e.survivorTarget()
+}
+
+// restore undoes the eliminations of the question at the given index, when it
+// is re-opened.
+func (e *Execution) restore(question int) {
+	for i := range e.Participants {
+		if e.Participants[i].EliminatedIn == question+1 {
+			e.Participants[i].Eliminated = false
+			e.Participants[i].EliminatedIn = 0
+		}
+	}
+}
+
+// addTiebreak adds a sudden death question to the end of the execution. It is
+// a copy of a question drawn from the quiz, under an ID of its own so that the
+// answers to the original question are kept apart. It is not part of any
+// section, so that it neither ends a round nor is scored as one.
+func (e *Execution) addTiebreak() {
+	quizQuestions := []quizzer.Question{}
+	for _, q := range e.Questions {
+		if !e.Tiebreaks[q.ID] {
+			quizQuestions = append(quizQuestions, q)
+		}
+	}
+
+	n := strconv.Itoa(len(e.Tiebreaks) + 1)
+	r := rand.New(rand.NewSource(e.seedFor("tiebreak", n)))
+	tiebreak := quizQuestions[r.Intn(len(quizQuestions))]
+	tiebreak.ID = fmt.Sprintf("%s-tiebreak-%s", tiebreak.ID, n)
+	tiebreak.SectionID = nil
+
+	e.Questions = append(e.Questions, tiebreak)
+	e.Tiebreaks[tiebreak.ID] = true
+}
@@ -0,0 +1,146 @@
+package execution
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/william-joh/quizzer/server/internal/quizzer"
+)
+
+func TestEliminate(t *testing.T) {
+	question := quizzer.Question{ID: "q1", Options: []quizzer.AnswerOption{
+		{ID: "o1", IsCorrect: true},
+		{ID: "o2"},
+	}}
+
+	tests := []struct {
+		name string
+		// answers holds the answer of each participant, "" for none, and
+		// "-" for participants that were already eliminated.
+		answers    []string
+		eliminated []bool
+		over       bool
+	}{
+		{
+			name:       "wrong and missing answers are eliminated",
+			answers:    []string{"o1", "o2", "", "o1"},
+			eliminated: []bool{false, true, true, false},
+		},
+		{
+			name:       "last one standing",
+			answers:    []string{"o2", "o1", "o2"},
+			eliminated: []bool{true, false, true},
+			over:       true,
+		},
+		{
+			name:       "nobody is eliminated if everyone fails",
+			answers:    []string{"o2", "", "o2"},
+			eliminated: []bool{false, false, false},
+		},
+		{
+			name:       "eliminated participants stay out",
+			answers:    []string{"-", "o1", "o2", "o1"},
+			eliminated: []bool{true, false, true, false},
+		},
+		{
+			name:       "everyone failing among the remaining",
+			answers:    []string{"-", "o2", ""},
+			eliminated: []bool{true, false, false},
+		},
+		{
+			name: "nobody left",
+			over: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestExecution(Settings{Elimination: true}, question)
+			for i, answer := range tt.answers {
+				p := Participant{ID: string(rune('a' + i)), Answers: map[string]string{}}
+				switch answer {
+				case "":
+				case "-":
+					p.Eliminated = true
+				default:
+					p.Answers[question.ID] = answer
+				}
+				e.Participants = append(e.Participants, p)
+			}
+
+			e.eliminate(0)
+			e.CurrentQuestion++
+
+			eliminated := []bool{}
+			for _, p := range e.Participants {
+				eliminated = append(eliminated, p.Eliminated)
+				if p.Eliminated && tt.answers[len(eliminated)-1] != "-" {
+					require.Equal(t, 1, p.EliminatedIn)
+				}
+			}
+			require.Equal(t, len(tt.eliminated), len(eliminated))
+			if len(tt.eliminated) > 0 {
+				require.Equal(t, tt.eliminated, eliminated)
+			}
+			require.Equal(t, tt.over, e.isOver())
+			// Ties are broken with more questions until it is over.
+			require.Equal(t, !tt.over, e.hasNextQuestion())
+		})
+	}
+}
+
+func TestAddTiebreak(t *testing.T) {
+	section := "section-id"
+	questions := []quizzer.Question{
+		{ID: "q1", Question: "q1", SectionID: &section},
+		{ID: "q2", Question: "q2", SectionID: &section},
+		{ID: "q3", Question: "q3"},
+	}
+
+	tests := []struct {
+		name      string
+		tiebreaks int
+	}{
+		{name: "first", tiebreaks: 1},
+		{name: "several", tiebreaks: 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestExecution(Settings{Elimination: true}, questions...)
+			e.Sections = []quizzer.Section{{ID: section, Multiplier: 2}}
+			e.Seed = 42
+
+			for range tt.tiebreaks {
+				e.addTiebreak()
+			}
+
+			require.Len(t, e.Questions, len(questions)+tt.tiebreaks)
+			require.Equal(t, questions, e.Questions[:len(questions)])
+			require.Len(t, e.Tiebreaks, tt.tiebreaks)
+
+			ids := map[string]bool{}
+			for _, q := range e.Questions[len(questions):] {
+				require.True(t, e.Tiebreaks[q.ID])
+				require.False(t, ids[q.ID], "tiebreak ID %s is used twice", q.ID)
+				ids[q.ID] = true
+
+				// Tiebreaks are copies of the questions of the quiz, never
+				// of other tiebreaks, and are not part of their section.
+				original, _, ok := strings.Cut(q.ID, "-tiebreak-")
+				require.True(t, ok)
+				require.Contains(t, []string{"q1", "q2", "q3"}, original)
+				require.Equal(t, original, q.Question)
+				require.Nil(t, q.SectionID)
+				require.Equal(t, 1, e.multiplier(q))
+			}
+
+			// The same seed draws the same tiebreaks.
+			again := newTestExecution(Settings{Elimination: true}, questions...)
+			again.Seed = e.Seed
+			for range tt.tiebreaks {
+				again.addTiebreak()
+			}
+			require.Equal(t, e.Questions, again.Questions)
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package execution
 
 import (
+	"crypto/subtle"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"slices"
@@ -12,33 +14,149 @@ import (
 	"github.com/william-joh/quizzer/server/internal/quizzer"
 )
 
+// Conn is a connection to a host, participant or spectator that the state of
+// the quiz is sent over. It is usually a websocket, but participants may also
+// follow the quiz with server-sent events.
+type Conn interface {
+	WriteJSON(v interface{}) error
+	Close() error
+}
+
 type Participant struct {
-	Conn    *websocket.Conn
-	ID      string            `json:"userId"`
-	Name    string            `json:"name"`
+	Conn    Conn
+	ID      string `json:"userId"`
+	Name    string `json:"name"`
+	IsGuest bool   `json:"isGuest"`
+	// Avatar and Color are picked by the participant from Avatars and
+	// Colors.
+	Avatar string `json:"avatar"`
+	Color  string `json:"color"`
+	// Team is the name of the team the participant plays in, if the quiz is
+	// played in teams.
+	Team string `json:"team,omitempty"`
+	// Eliminated is set when the participant is out of an elimination
+	// execution. They follow the rest of it as a spectator.
+	Eliminated bool `json:"eliminated"`
+	// EliminatedIn is the number of the question that eliminated the
+	// participant, starting at 1. It is 0 for participants that joined after
+	// the execution started.
+	EliminatedIn int `json:"eliminatedIn,omitempty"`
+	// Muted is set when the host hides the reactions and chat messages of the
+	// participant.
+	Muted bool `json:"muted"`
+	// Answers holds the ID of the chosen answer option for each question.
 	Answers map[string]string `json:"answers"`
+	// Scores holds the points given for each finished question.
+	Scores map[string]int `json:"scores"`
+	// answerTimeLeft holds the time that was left on the question timer when
+	// each answer was given.
+	answerTimeLeft map[string]time.Duration
+	// answeredAt holds when each question was first answered.
+	answeredAt map[string]time.Time
+	// pings is how many pings the participant has answered since their clock
+	// was last synced.
+	pings int
+	// roundTrip is the lowest round trip time measured to the participant.
+	roundTrip time.Duration
+	// clockOffset is how far the clock of the participant is ahead.
+	clockOffset time.Duration
+	// pingNonce identifies the ping the participant has yet to answer, and
+	// pingSentAt is when it was sent. Only the answer to that ping is
+	// measured.
+	pingNonce  string
+	pingSentAt time.Time
+	// lastReactionAt and lastChatAt are when the participant last reacted and
+	// chatted in the lobby.
+	lastReactionAt time.Time
+	lastChatAt     time.Time
+}
+
+// ErrClosed is returned by HandleMessages once the connection has been closed
+// and no more messages can be read from it.
+var ErrClosed = errors.New("connection closed")
+
+// ErrNotJoined is returned when handling an action for someone that has not
+// joined the execution.
+var ErrNotJoined = errors.New("not joined")
+
+// ErrWrongPassword is returned when joining an execution with the wrong join
+// password.
+var ErrWrongPassword = errors.New("wrong join password")
+
+// Identity is who is behind a connection, as established when the connection
+// was opened.
+type Identity struct {
+	ID      string
+	Name    string
+	IsGuest bool
 }
 
 type Phase string
 
 const (
-	PhaseLobby    Phase = "lobby"
-	PhaseQuestion Phase = "question"
-	PhaseResults  Phase = "results"
+	PhaseLobby Phase = "lobby"
+	// PhaseCountdown shows the question before its answer options unlock.
+	PhaseCountdown Phase = "countdown"
+	PhaseQuestion  Phase = "question"
+	PhasePaused    Phase = "paused"
+	// PhaseReveal shows the answer to the question that just finished before
+	// moving on to the leaderboard.
+	PhaseReveal  Phase = "reveal"
+	PhaseResults Phase = "results"
 )
 
 type Execution struct {
-	CreatedAt       time.Time          `json:"createdAt"`
-	Code            string             `json:"id"`
-	Quiz            quizzer.Quiz       `json:"quiz"`
-	Questions       []quizzer.Question `json:"questions"`
-	Host            quizzer.User       `json:"host"`
-	HostConn        *websocket.Conn
-	Participants    []Participant `json:"participants"`
-	Phase           Phase         `json:"phase"`
-	CurrentQuestion int           `json:"currentQuestion"`
-	IsDone          bool          `json:"isDone"`
-	done            chan bool
+	CreatedAt time.Time          `json:"createdAt"`
+	Code      string             `json:"id"`
+	Quiz      quizzer.Quiz       `json:"quiz"`
+	Questions []quizzer.Question `json:"questions"`
+	// Sections are the rounds of the quiz, in order.
+	Sections []quizzer.Section `json:"sections"`
+	// Slides are shown between the questions, in order.
+	Slides       []Slide      `json:"slides"`
+	Host         quizzer.User `json:"host"`
+	Settings     Settings     `json:"settings"`
+	HostConn     Conn
+	Participants []Participant `json:"participants"`
+	// CoHostIDs are the users the host has allowed to control the execution.
+	CoHostIDs []string `json:"coHostIds"`
+	// CoHosts are the connected co-hosts.
+	CoHosts []CoHost
+	// Spectators receive the same state as the host, but cannot control the
+	// execution.
+	Spectators      []Conn
+	Phase           Phase `json:"phase"`
+	CurrentQuestion int   `json:"currentQuestion"`
+	// CurrentSlide is the index of the slide that is shown, or shown next.
+	CurrentSlide int  `json:"currentSlide"`
+	IsDone       bool `json:"isDone"`
+	// QuestionDeadline is when the current question finishes on its own. It is
+	// zero if the question has no time limit.
+	QuestionDeadline time.Time `json:"questionDeadline"`
+	// QuestionStartedAt is when the answer options of the current question
+	// were unlocked.
+	QuestionStartedAt time.Time `json:"questionStartedAt"`
+	// PhaseDeadline is when the countdown or reveal phase is over, or when the
+	// results phase is over on auto-pilot.
+	PhaseDeadline time.Time `json:"phaseDeadline"`
+	// SkippedQuestions holds the IDs of questions that were skipped by the host
+	// and should not be scored.
+	SkippedQuestions map[string]bool `json:"skippedQuestions"`
+	// Tiebreaks holds the IDs of the sudden death questions that were added to
+	// an elimination execution after the questions of the quiz ran out.
+	Tiebreaks map[string]bool `json:"tiebreaks"`
+	// Seed makes the shuffling of questions and answer options deterministic
+	// for the execution.
+	Seed int64 `json:"seed"`
+	// departed holds the participants that left after the execution started.
+	departed []Participant
+	// unsavedOptions holds the IDs of answer options that were added to a
+	// question while running and only exist in the execution.
+	unsavedOptions map[string]bool
+	// remaining is the time left on the question timer when it was paused.
+	remaining time.Duration
+	done      chan bool
+	mu        sync.Mutex
 }
 
 type QuizState struct {
@@ -53,9 +171,30 @@ type Message struct {
 	Data   interface{} `json:"data"`
 }
 
+// option is an answer option as it is shown to hosts and participants, without
+// revealing whether it is correct.
+type option struct {
+	ID       string  `json:"id"`
+	Text     string  `json:"text"`
+	ImageURL *string `json:"imageUrl,omitempty"`
+	AudioURL *string `json:"audioUrl,omitempty"`
+}
+
+func toOption(o quizzer.AnswerOption) option {
+	return option{ID: o.ID, Text: o.Text, ImageURL: o.ImageURL, AudioURL: o.AudioURL}
+}
+
+func toOptions(options []quizzer.AnswerOption) []option {
+	result := []option{}
+	for _, o := range options {
+		result = append(result, toOption(o))
+	}
+	return result
+}
+
 func (e *Execution) Run() {
 	go func() {
-		ticker := time.NewTicker(time.Second * 2)
+		ticker := time.NewTicker(time.Millisecond * 500)
 		defer ticker.Stop()
 
 		for {
@@ -65,52 +204,104 @@ func (e *Execution) Run() {
 				return
 			case <-ticker.C:
 				log.Trace().Msg("Checking for finished questions")
-				if e.Phase != PhaseQuestion {
-					continue
-				}
-
-				// Check if all participants have answered
-				allAnswered := true
-				for _, p := range e.Participants {
-					if _, ok := p.Answers[e.Questions[e.CurrentQuestion].ID]; !ok {
-						allAnswered = false
-						break
-					}
-				}
-
-				if allAnswered {
-					if err := e.handleFinishQuestionMsg(e.HostConn); err != nil {
-						log.Error().Err(err).Msg("Failed to finish question")
-					}
-				}
+				e.mu.Lock()
+				e.checkPhaseDeadline()
+				e.checkQuestionFinished()
+				e.mu.Unlock()
 			}
 		}
 	}()
 }
 
-func (e *Execution) HandleMessages(conn *websocket.Conn) error {
-	var msg Message
-	if err := conn.ReadJSON(&msg); err != nil {
+func (e *Execution) checkQuestionFinished() {
+	if e.Phase != PhaseQuestion {
+		return
+	}
+
+	timeUp := !e.QuestionDeadline.IsZero() && time.Now().After(e.QuestionDeadline)
+
+	// Check if all participants have answered
+	allAnswered := true
+	for _, p := range e.activeParticipants() {
+		if _, ok := p.Answers[e.Questions[e.CurrentQuestion].ID]; !ok {
+			allAnswered = false
+			break
+		}
+	}
+
+	if timeUp || allAnswered {
+		if err := e.finish(); err != nil {
+			log.Error().Err(err).Msg("Failed to finish question")
+		}
+	}
+}
+
+// HandleMessages reads a message from the websocket and handles it. Messages
+// that cannot be handled are answered with an error message, and the
+// connection can be used for further messages. Once nothing more can be read
+// from the connection, ErrClosed is returned and the caller disconnects it.
+func (e *Execution) HandleMessages(conn *websocket.Conn, identity Identity) error {
+	_, data, err := conn.ReadMessage()
+	if err != nil {
 		var closeErr *websocket.CloseError
 		if errors.As(err, &closeErr) {
 			log.Debug().Err(closeErr).Msg("Connection closed")
-
-			if err := e.handleCloseMsg(conn); err != nil {
-				log.Error().Err(err).Msg("Failed to handle close message")
-				return fmt.Errorf("handle close message: %w", err)
-			}
-			return nil
+		} else {
+			log.Error().Err(err).Msg("Failed to read message")
 		}
+		return fmt.Errorf("%w: %w", ErrClosed, err)
+	}
 
-		log.Error().Err(err).Msg("Failed to read message")
-		return fmt.Errorf("read message: %w", err)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var msg Message
+	if err := json.Unmarshal(data, &msg); err != nil {
+		log.Error().Err(err).Msg("Failed to parse message")
+		err = fmt.Errorf("parse message: %w", err)
+		sendError(conn, err)
+		return err
 	}
 	log.Debug().Any("msg", msg).Msg("Received message")
 
+	if err := e.handleMessage(conn, identity, msg); err != nil {
+		// Messages sent too fast are dropped without telling.
+		if !errors.Is(err, ErrRateLimited) {
+			sendError(conn, errors.Unwrap(err))
+		}
+		return err
+	}
+
+	return nil
+}
+
+// sendError tells the other end of the connection that its message could not
+// be handled.
+func sendError(conn Conn, err error) {
+	if err := conn.WriteJSON(struct {
+		Type  string `json:"type"`
+		Error string `json:"error"`
+	}{
+		Type:  "error",
+		Error: err.Error(),
+	}); err != nil {
+		log.Error().Err(err).Msg("Failed to send error")
+	}
+}
+
+// HandleMessage handles a message that was received on the connection.
+func (e *Execution) HandleMessage(conn Conn, identity Identity, msg Message) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.handleMessage(conn, identity, msg)
+}
+
+func (e *Execution) handleMessage(conn Conn, identity Identity, msg Message) error {
 	var err error
 	switch msg.Type {
 	case "Join":
-		err = e.handleJoinMsg(conn, msg)
+		err = e.handleJoinMsg(conn, identity, msg)
 	case "Start":
 		err = e.handleStartMsg(conn)
 	case "End":
@@ -119,8 +310,40 @@ func (e *Execution) HandleMessages(conn *websocket.Conn) error {
 		err = e.handleFinishQuestionMsg(conn)
 	case "NextQuestion":
 		err = e.handleNextQuestionMsg(conn)
+	case "PauseQuestion":
+		err = e.handlePauseQuestionMsg(conn)
+	case "ResumeQuestion":
+		err = e.handleResumeQuestionMsg(conn)
+	case "SkipQuestion":
+		err = e.handleSkipQuestionMsg(conn)
+	case "ReopenQuestion":
+		err = e.handleReopenQuestionMsg(conn)
+	case "MediaEnded":
+		err = e.handleMediaEndedMsg(conn)
+	case "GrantCoHost":
+		err = e.handleGrantCoHostMsg(conn, msg)
+	case "RevokeCoHost":
+		err = e.handleRevokeCoHostMsg(conn, msg)
+	case "HandOver":
+		err = e.handleHandOverMsg(conn, msg)
+	case "SetAvatar":
+		err = e.handleSetAvatarMsg(conn, msg)
+	case "JoinTeam":
+		err = e.handleJoinTeamMsg(conn, msg)
 	case "AnswerQuestion":
 		err = e.handleAnswerQuestionMsg(conn, msg)
+	case "ClockSync":
+		err = e.handleClockSyncMsg(conn)
+	case "Pong":
+		err = e.handlePongMsg(conn, msg)
+	case "React":
+		err = e.handleReactMsg(conn, msg)
+	case "Chat":
+		err = e.handleChatMsg(conn, msg)
+	case "Mute":
+		err = e.handleMuteMsg(conn, msg, true)
+	case "Unmute":
+		err = e.handleMuteMsg(conn, msg, false)
 	default:
 		log.Error().Str("type", msg.Type).Msg("Unknown message type")
 		err = fmt.Errorf("unknown message type: %s", msg.Type)
@@ -134,19 +357,43 @@ func (e *Execution) HandleMessages(conn *websocket.Conn) error {
 	return nil
 }
 
-func (e *Execution) handleCloseMsg(conn *websocket.Conn) error {
+// HandleAction handles a message from a joined participant that was not
+// received on their connection, e.g. because server-sent events only go one
+// way.
+func (e *Execution) HandleAction(identity Identity, msg Message) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	p, ok := e.getParticipant(identity.ID)
+	if !ok {
+		log.Error().Str("userId", identity.ID).Msg("Participant has not joined")
+		return ErrNotJoined
+	}
+
+	return e.handleMessage(p.Conn, identity, msg)
+}
+
+// Disconnect removes the connection from the execution when it has been
+// closed by the other end.
+func (e *Execution) Disconnect(conn Conn) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.handleCloseMsg(conn)
+}
+
+func (e *Execution) handleCloseMsg(conn Conn) error {
 	log.Debug().Msg("Handling close message")
 	if e.HostConn == conn {
 		return e.handleEndMsg(conn)
 	}
-
-	for i, p := range e.Participants {
-		if p.Conn == conn {
-			e.Participants = append(e.Participants[:i], e.Participants[i+1:]...)
-			break
-		}
+	if e.IsDone {
+		// Everyone has been told that the quiz ended.
+		return nil
 	}
 
+	e.removeConn(conn)
+
 	// Broadcast the new quiz state
 	if err := e.broadcastQuizState(); err != nil {
 		log.Error().Err(err).Msg("Failed to broadcast quiz state")
@@ -156,49 +403,104 @@ func (e *Execution) handleCloseMsg(conn *websocket.Conn) error {
 	return nil
 }
 
+// removeConn stops sending to the connection of a participant, spectator or
+// co-host. Participants that leave after the execution started are kept apart,
+// so that their results are not lost.
+func (e *Execution) removeConn(conn Conn) {
+	e.Participants = slices.DeleteFunc(e.Participants, func(p Participant) bool {
+		if p.Conn != conn {
+			return false
+		}
+		if e.Phase != PhaseLobby {
+			p.Conn = nil
+			e.departed = append(e.departed, p)
+		}
+		return true
+	})
+	e.Spectators = slices.DeleteFunc(e.Spectators, func(c Conn) bool {
+		return c == conn
+	})
+	e.CoHosts = slices.DeleteFunc(e.CoHosts, func(c CoHost) bool {
+		return c.Conn == conn
+	})
+}
+
+// lifetime returns whether the execution is done, and when it was created.
+func (e *Execution) lifetime() (bool, time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.IsDone, e.CreatedAt
+}
+
+// allParticipants returns the participants, including those that left after
+// the execution started.
+func (e *Execution) allParticipants() []Participant {
+	return slices.Concat(e.Participants, e.departed)
+}
+
 func (e *Execution) Close() {
 	log.Debug().Msg("Closing execution")
 	for _, p := range e.Participants {
 		p.Conn.Close()
 	}
 
-	e.HostConn.Close()
-}
+	for _, c := range e.Spectators {
+		c.Close()
+	}
 
-func (e *Execution) handleJoinMsg(conn *websocket.Conn, msg Message) error {
-	data, ok := msg.Data.(map[string]interface{})
-	if !ok {
-		log.Error().Msgf("Failed to parse data, expected map[string]string, got %T", msg.Data)
-		return fmt.Errorf("parse data: expected map[string]string, got %T", msg.Data)
+	for _, c := range e.CoHosts {
+		c.Conn.Close()
 	}
 
-	username, ok := data["username"]
-	if !ok {
-		log.Error().Msg("Username not provided")
-		return fmt.Errorf("username not provided")
+	if e.HostConn != nil {
+		e.HostConn.Close()
 	}
+}
 
-	participantId, ok := data["id"]
-	if !ok {
-		log.Error().Msg("Participant ID not provided")
-		return fmt.Errorf("participant ID not provided")
+func (e *Execution) handleJoinMsg(conn Conn, identity Identity, msg Message) error {
+	// The join data is optional, it only carries the role and join password.
+	data, _ := msg.Data.(map[string]interface{})
+
+	if e.isJoined(conn) {
+		log.Error().Msg("Connection already joined")
+		return fmt.Errorf("connection already joined")
 	}
 
-	if participantId == e.Host.ID {
+	if role, _ := data["role"].(string); role == RoleSpectator {
+		if err := e.checkJoinPassword(data); err != nil {
+			return err
+		}
+		e.Spectators = append(e.Spectators, conn)
+	} else if identity.ID == "" {
+		log.Error().Msg("Anonymous connections can only join as spectators")
+		return fmt.Errorf("anonymous connections can only join as spectators")
+	} else if !identity.IsGuest && identity.ID == e.Host.ID {
 		e.HostConn = conn
+	} else if !identity.IsGuest && slices.Contains(e.CoHostIDs, identity.ID) {
+		e.CoHosts = append(e.CoHosts, CoHost{Conn: conn, ID: identity.ID, Name: identity.Name})
 	} else {
-		if _, ok := e.getParticipant(participantId.(string)); ok {
+		if identity.IsGuest && e.Settings.RequireAccount {
+			log.Error().Msg("Guests are not allowed to join")
+			return fmt.Errorf("an account is required to join this quiz")
+		}
+
+		if err := e.checkJoinPassword(data); err != nil {
+			return err
+		}
+
+		if _, ok := e.getParticipant(identity.ID); ok {
 			log.Error().Msg("Participant already joined")
 			return fmt.Errorf("participant already joined")
 		}
 
-		participant := Participant{
-			Conn:    conn,
-			ID:      participantId.(string),
-			Name:    username.(string),
-			Answers: make(map[string]string),
+		// Participants that left come back with their answers and scores,
+		// even if others cannot join late.
+		if !e.rejoin(conn, identity.ID) {
+			if err := e.addParticipant(conn, identity, data); err != nil {
+				return err
+			}
 		}
-		e.Participants = append(e.Participants, participant)
 	}
 
 	// Broadcast the new quiz state
@@ -207,16 +509,109 @@ func (e *Execution) handleJoinMsg(conn *websocket.Conn, msg Message) error {
 		return fmt.Errorf("broadcast quiz state: %w", err)
 	}
 
+	// Measure the latency of the participant for answer timing. The ping is
+	// sent after the state, so that the client knows the quiz when it comes.
+	if p, ok := e.getParticipantByConn(conn); ok {
+		if err := p.sendPing(); err != nil {
+			log.Error().Err(err).Msg("Failed to send ping")
+		}
+	}
+
 	return nil
 }
 
-func (e *Execution) handleStartMsg(conn *websocket.Conn) error {
-	if e.HostConn != conn {
-		log.Error().Msg("Only the host can start the quiz")
-		return fmt.Errorf("only the host can start the quiz")
+// addParticipant adds a new participant to the execution.
+func (e *Execution) addParticipant(conn Conn, identity Identity, data map[string]interface{}) error {
+	if e.Phase != PhaseLobby && !e.Settings.AllowLateJoin {
+		log.Error().Msg("Quiz has already started")
+		return fmt.Errorf("quiz has already started")
 	}
 
-	e.Phase = PhaseQuestion
+	avatar, color := e.defaultLook()
+	avatar, color, err := look(data, avatar, color)
+	if err != nil {
+		return err
+	}
+
+	participant := Participant{
+		Conn:           conn,
+		ID:             identity.ID,
+		Name:           identity.Name,
+		IsGuest:        identity.IsGuest,
+		Avatar:         avatar,
+		Color:          color,
+		Team:           e.smallestTeam(),
+		Answers:        make(map[string]string),
+		Scores:         make(map[string]int),
+		answerTimeLeft: make(map[string]time.Duration),
+		answeredAt:     make(map[string]time.Time),
+	}
+
+	// Participants that missed questions of an elimination execution
+	// can only follow the rest of it.
+	if e.Settings.Elimination && e.Phase != PhaseLobby {
+		participant.Eliminated = true
+	}
+
+	e.Participants = append(e.Participants, participant)
+	return nil
+}
+
+// rejoin brings back the participant with the ID on the connection, if they
+// left the execution after it started. It returns false if they did not.
+func (e *Execution) rejoin(conn Conn, participantID string) bool {
+	i := slices.IndexFunc(e.departed, func(p Participant) bool { return p.ID == participantID })
+	if i < 0 {
+		return false
+	}
+
+	participant := e.departed[i]
+	participant.Conn = conn
+	e.departed = slices.Delete(e.departed, i, i+1)
+	e.Participants = append(e.Participants, participant)
+	return true
+}
+
+// checkJoinPassword returns ErrWrongPassword unless the join data has the join
+// password of the execution, if it has one.
+func (e *Execution) checkJoinPassword(data map[string]interface{}) error {
+	if e.Settings.JoinPassword == "" {
+		return nil
+	}
+
+	password, _ := data["password"].(string)
+	if subtle.ConstantTimeCompare([]byte(password), []byte(e.Settings.JoinPassword)) != 1 {
+		log.Error().Msg("Wrong join password")
+		return ErrWrongPassword
+	}
+	return nil
+}
+
+func (e *Execution) handleStartMsg(conn Conn) error {
+	if !e.canControl(conn) {
+		log.Error().Msg("Only a host can start the quiz")
+		return fmt.Errorf("only a host can start the quiz")
+	}
+
+	return e.start()
+}
+
+func (e *Execution) start() error {
+	if e.Phase != PhaseLobby {
+		log.Error().Msg("Quiz has already started")
+		return fmt.Errorf("quiz has already started")
+	}
+
+	if len(e.Questions) == 0 {
+		log.Error().Msg("Quiz has no questions")
+		return fmt.Errorf("quiz has no questions")
+	}
+
+	if e.hasPendingSlide() {
+		e.showSlide()
+	} else {
+		e.startQuestion()
+	}
 
 	// Broadcast the new quiz state
 	if err := e.broadcastQuizState(); err != nil {
@@ -227,26 +622,53 @@ func (e *Execution) handleStartMsg(conn *websocket.Conn) error {
 	return nil
 }
 
-func (e *Execution) handleEndMsg(conn *websocket.Conn) error {
+func (e *Execution) handleEndMsg(conn Conn) error {
 	if e.HostConn != conn {
 		log.Error().Msg("Only the host can end the quiz")
 		return fmt.Errorf("only the host can end the quiz")
 	}
 
+	return e.end()
+}
+
+func (e *Execution) end() error {
+	if e.IsDone {
+		return nil
+	}
+
 	var wg sync.WaitGroup
 	for _, participant := range e.Participants {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			sendWsClose(participant.Conn)
+			sendClose(participant.Conn)
 		}()
 	}
 
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		sendWsClose(e.HostConn)
-	}()
+	for _, spectator := range e.Spectators {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sendClose(spectator)
+		}()
+	}
+
+	for _, coHost := range e.CoHosts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sendClose(coHost.Conn)
+		}()
+	}
+
+	// The host may control the quiz without being connected to it.
+	if e.HostConn != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sendClose(e.HostConn)
+		}()
+	}
 
 	wg.Wait()
 	e.IsDone = true
@@ -255,9 +677,18 @@ func (e *Execution) handleEndMsg(conn *websocket.Conn) error {
 	return nil
 }
 
-func sendWsClose(conn *websocket.Conn) {
+// sendClose tells the other end of the connection that the quiz has ended.
+func sendClose(conn Conn) {
+	ws, ok := conn.(*websocket.Conn)
+	if !ok {
+		if err := conn.Close(); err != nil {
+			log.Error().Err(err).Msg("Failed to close connection")
+		}
+		return
+	}
+
 	log.Debug().Msg("Closing connection")
-	if err := conn.WriteControl(
+	if err := ws.WriteControl(
 		websocket.CloseMessage,
 		websocket.FormatCloseMessage(websocket.CloseNormalClosure, "QUIZ_END"),
 		time.Now().Add(time.Second*5)); err != nil {
@@ -265,19 +696,115 @@ func sendWsClose(conn *websocket.Conn) {
 	}
 }
 
-func (e *Execution) handleFinishQuestionMsg(conn *websocket.Conn) error {
-	if e.HostConn != conn {
-		log.Error().Msg("Only the host can finish a question")
-		return fmt.Errorf("only the host can finish a question")
+func (e *Execution) handleFinishQuestionMsg(conn Conn) error {
+	if !e.canControl(conn) {
+		log.Error().Msg("Only a host can finish a question")
+		return fmt.Errorf("only a host can finish a question")
+	}
+
+	return e.finish()
+}
+
+func (e *Execution) finish() error {
+	if e.Phase != PhaseQuestion && e.Phase != PhasePaused {
+		log.Error().Msg("Not in question phase")
+		return fmt.Errorf("not in question phase")
+	}
+
+	e.finishQuestion()
+
+	// Broadcast the new quiz state
+	if err := e.broadcastQuizState(); err != nil {
+		log.Error().Err(err).Msg("Failed to broadcast quiz state")
+		return fmt.Errorf("broadcast quiz state: %w", err)
+	}
+
+	return nil
+}
+
+func (e *Execution) handleNextQuestionMsg(conn Conn) error {
+	if !e.canControl(conn) {
+		log.Error().Msg("Only a host can move to the next question")
+		return fmt.Errorf("only a host can move to the next question")
+	}
+
+	return e.next()
+}
+
+func (e *Execution) next() error {
+	if e.Phase == PhaseFinished {
+		log.Error().Msg("Quiz has finished")
+		return fmt.Errorf("quiz has finished")
+	}
+
+	// Moving on from the countdown, media or reveal does not wait for them
+	// to run out.
+	if e.Phase == PhaseCountdown || e.Phase == PhaseMedia || e.Phase == PhaseReveal {
+		switch e.Phase {
+		case PhaseCountdown:
+			e.playMedia()
+		case PhaseMedia:
+			e.openQuestion()
+		default:
+			e.showResults()
+		}
+
+		if err := e.broadcastQuizState(); err != nil {
+			log.Error().Err(err).Msg("Failed to broadcast quiz state")
+			return fmt.Errorf("broadcast quiz state: %w", err)
+		}
+		return nil
+	}
+
+	if e.Phase == PhaseSlide {
+		e.CurrentSlide++
+	}
+
+	switch {
+	case e.hasPendingSlide():
+		e.showSlide()
+	case e.hasNextQuestion():
+		if e.CurrentQuestion >= len(e.Questions) {
+			// Too many participants survived the quiz, so sudden death
+			// questions decide between them.
+			e.addTiebreak()
+		}
+		e.startQuestion()
+	default:
+		// Nothing is left to show but the final results.
+		e.Phase = PhaseFinished
+		e.PhaseDeadline = time.Time{}
+	}
+
+	// Broadcast the new quiz state
+	if err := e.broadcastQuizState(); err != nil {
+		log.Error().Err(err).Msg("Failed to broadcast quiz state")
+		return fmt.Errorf("broadcast quiz state: %w", err)
+	}
+
+	return nil
+}
+
+func (e *Execution) handlePauseQuestionMsg(conn Conn) error {
+	if !e.canControl(conn) {
+		log.Error().Msg("Only a host can pause a question")
+		return fmt.Errorf("only a host can pause a question")
 	}
 
+	return e.pause()
+}
+
+func (e *Execution) pause() error {
 	if e.Phase != PhaseQuestion {
 		log.Error().Msg("Not in question phase")
 		return fmt.Errorf("not in question phase")
 	}
 
-	e.Phase = PhaseResults
-	e.CurrentQuestion++
+	e.Phase = PhasePaused
+	if !e.QuestionDeadline.IsZero() {
+		e.remaining = time.Until(e.QuestionDeadline)
+		e.QuestionDeadline = time.Time{}
+	}
 
 	// Broadcast the new quiz state
 	if err := e.broadcastQuizState(); err != nil {
@@ -288,18 +815,26 @@ func (e *Execution) handleFinishQuestionMsg(conn *websocket.Conn) error {
 	return nil
 }
 
-func (e *Execution) handleNextQuestionMsg(conn *websocket.Conn) error {
-	if e.HostConn != conn {
-		log.Error().Msg("Only the host can move to the next question")
-		return fmt.Errorf("only the host can move to the next question")
+func (e *Execution) handleResumeQuestionMsg(conn Conn) error {
+	if !e.canControl(conn) {
+		log.Error().Msg("Only a host can resume a question")
+		return fmt.Errorf("only a host can resume a question")
 	}
 
-	if e.CurrentQuestion >= len(e.Questions) {
-		log.Error().Msg("No more questions to show")
-		return fmt.Errorf("no more questions to show")
+	return e.resume()
+}
+
+func (e *Execution) resume() error {
+	if e.Phase != PhasePaused {
+		log.Error().Msg("Question is not paused")
+		return fmt.Errorf("question is not paused")
 	}
 
 	e.Phase = PhaseQuestion
+	if e.remaining > 0 {
+		e.QuestionDeadline = time.Now().Add(e.remaining)
+		e.remaining = 0
+	}
 
 	// Broadcast the new quiz state
 	if err := e.broadcastQuizState(); err != nil {
@@ -310,32 +845,190 @@ func (e *Execution) handleNextQuestionMsg(conn *websocket.Conn) error {
 	return nil
 }
 
-func (e *Execution) handleAnswerQuestionMsg(_ *websocket.Conn, msg Message) error {
+func (e *Execution) handleSkipQuestionMsg(conn Conn) error {
+	if !e.canControl(conn) {
+		log.Error().Msg("Only a host can skip a question")
+		return fmt.Errorf("only a host can skip a question")
+	}
+
+	return e.skip()
+}
+
+func (e *Execution) skip() error {
+	if e.Phase != PhaseCountdown && e.Phase != PhaseMedia && e.Phase != PhaseQuestion && e.Phase != PhasePaused {
+		log.Error().Msg("Not in question phase")
+		return fmt.Errorf("not in question phase")
+	}
+
+	e.SkippedQuestions[e.Questions[e.CurrentQuestion].ID] = true
+	e.finishQuestion()
+
+	// Broadcast the new quiz state
+	if err := e.broadcastQuizState(); err != nil {
+		log.Error().Err(err).Msg("Failed to broadcast quiz state")
+		return fmt.Errorf("broadcast quiz state: %w", err)
+	}
+
+	return nil
+}
+
+func (e *Execution) handleReopenQuestionMsg(conn Conn) error {
+	if !e.canControl(conn) {
+		log.Error().Msg("Only a host can re-open a question")
+		return fmt.Errorf("only a host can re-open a question")
+	}
+
+	return e.reopen()
+}
+
+func (e *Execution) reopen() error {
+	if (e.Phase != PhaseResults && e.Phase != PhaseIntermission && e.Phase != PhaseFinished) || e.CurrentQuestion == 0 {
+		log.Error().Msg("No finished question to re-open")
+		return fmt.Errorf("no finished question to re-open")
+	}
+
+	e.CurrentQuestion--
+	questionID := e.Questions[e.CurrentQuestion].ID
+	delete(e.SkippedQuestions, questionID)
+	// The question is answered again from scratch.
+	for _, p := range e.allParticipants() {
+		delete(p.Answers, questionID)
+		delete(p.answerTimeLeft, questionID)
+		delete(p.answeredAt, questionID)
+		delete(p.Scores, questionID)
+	}
+	e.restore(e.CurrentQuestion)
+	e.startQuestion()
+
+	// Broadcast the new quiz state
+	if err := e.broadcastQuizState(); err != nil {
+		log.Error().Err(err).Msg("Failed to broadcast quiz state")
+		return fmt.Errorf("broadcast quiz state: %w", err)
+	}
+
+	return nil
+}
+
+// startQuestion moves the execution into the question phase for the current
+// question and starts its timer, after counting down to it if configured.
+func (e *Execution) startQuestion() {
+	e.remaining = 0
+	e.QuestionDeadline = time.Time{}
+
+	if e.Settings.CountdownSeconds > 0 {
+		e.Phase = PhaseCountdown
+		e.PhaseDeadline = time.Now().Add(time.Duration(e.Settings.CountdownSeconds) * time.Second)
+		return
+	}
+
+	e.playMedia()
+}
+
+// finishQuestion stops the timer of the current question, scores it unless it
+// was skipped and reveals its answer.
+func (e *Execution) finishQuestion() {
+	q := e.Questions[e.CurrentQuestion]
+	if !e.SkippedQuestions[q.ID] {
+		for _, p := range e.allParticipants() {
+			p.Scores[q.ID] = e.score(q, p)
+		}
+
+		if e.Settings.Elimination {
+			e.eliminate(e.CurrentQuestion)
+		}
+	}
+
+	e.remaining = 0
+	e.QuestionDeadline = time.Time{}
+	e.CurrentQuestion++
+
+	if e.SkippedQuestions[q.ID] {
+		e.showResults()
+	} else {
+		e.reveal()
+	}
+}
+
+// timeLeft returns the number of whole seconds left to answer the current
+// question, or 0 if the question has no time limit.
+func (e *Execution) timeLeft() uint64 {
+	return uint64(e.timeLeftDuration().Round(time.Second) / time.Second)
+}
+
+func (e *Execution) timeLeftDuration() time.Duration {
+	left := e.remaining
+	if !e.QuestionDeadline.IsZero() {
+		left = time.Until(e.QuestionDeadline)
+	}
+
+	if left <= 0 {
+		return 0
+	}
+	return left
+}
+
+func (e *Execution) handleAnswerQuestionMsg(conn Conn, msg Message) error {
 	data, ok := msg.Data.(map[string]interface{})
 	if !ok {
 		log.Error().Msgf("Failed to parse data, expected map[string]string, got %T", msg.Data)
 		return fmt.Errorf("parse data: expected map[string]string, got %T", msg.Data)
 	}
 
-	participantId, ok := data["id"]
+	// Answers are always attributed to the participant that joined on the
+	// connection, never to an ID supplied by the client.
+	participant, ok := e.getParticipantByConn(conn)
 	if !ok {
-		log.Error().Msg("Participant ID not provided")
-		return fmt.Errorf("participant ID not provided")
+		log.Error().Msg("Connection has not joined as a participant")
+		return fmt.Errorf("connection has not joined as a participant")
 	}
 
-	participant, ok := e.getParticipant(participantId.(string))
-	if !ok {
-		log.Error().Msg("Participant not found")
-		return fmt.Errorf("participant not found")
+	if participant.Eliminated {
+		log.Error().Str("participantId", participant.ID).Msg("Participant has been eliminated")
+		return fmt.Errorf("participant has been eliminated")
 	}
 
-	answer, ok := data["answer"]
+	if e.Phase != PhaseQuestion {
+		log.Error().Msg("Not in question phase")
+		return fmt.Errorf("not in question phase")
+	}
+
+	optionID, ok := data["optionId"].(string)
 	if !ok {
-		log.Error().Msg("Answer not provided")
-		return fmt.Errorf("answer not provided")
+		log.Error().Msg("Option ID not provided")
+		return fmt.Errorf("option ID not provided")
+	}
+
+	q := e.Questions[e.CurrentQuestion]
+	if _, ok := q.Option(optionID); !ok {
+		log.Error().Str("optionId", optionID).Msg("Option not found")
+		return fmt.Errorf("option %s not found", optionID)
+	}
+
+	if answeredAt, ok := participant.answeredAt[q.ID]; ok {
+		window := time.Duration(e.Settings.AnswerChangeWindowSeconds) * time.Second
+		if time.Since(answeredAt) > window {
+			log.Error().Str("participantId", participant.ID).Msg("Question already answered")
+			return fmt.Errorf("question already answered")
+		}
+	} else {
+		participant.answeredAt[q.ID] = time.Now()
 	}
 
-	participant.Answers[e.Questions[e.CurrentQuestion].ID] = answer.(string)
+	// The client may report how long after receiving the question it was
+	// answered, to be compensated for its latency.
+	var clientElapsed *time.Duration
+	if ms, ok := data["elapsedMs"].(float64); ok {
+		elapsed := time.Duration(ms * float64(time.Millisecond))
+		clientElapsed = &elapsed
+	}
+
+	timeLeft := e.timeLeftDuration()
+	if !e.QuestionDeadline.IsZero() {
+		timeLeft = min(timeLeft+e.latencyCompensation(participant, clientElapsed), e.questionLimit(q))
+	}
+
+	participant.Answers[q.ID] = optionID
+	participant.answerTimeLeft[q.ID] = timeLeft
 
 	// Broadcast the new quiz state
 	if err := e.broadcastQuizState(); err != nil {
@@ -346,6 +1039,9 @@ func (e *Execution) handleAnswerQuestionMsg(_ *websocket.Conn, msg Message) erro
 	return nil
 }
 
+// broadcastQuizState sends the state of the quiz to everyone. A connection
+// that cannot be written to does not keep the others from getting the state,
+// it is closed and no longer sent to instead.
 func (e *Execution) broadcastQuizState() error {
 	// Send quiz state to host
 	hostPayload, err := e.getHostPayload()
@@ -354,24 +1050,56 @@ func (e *Execution) broadcastQuizState() error {
 		return err
 	}
 
-	if err := e.HostConn.WriteJSON(hostPayload); err != nil {
-		log.Error().Err(err).Msg("Failed to send quiz state to host")
-		return err
+	var failed []Conn
+	if e.HostConn != nil {
+		if err := e.HostConn.WriteJSON(hostPayload); err != nil {
+			log.Error().Err(err).Msg("Failed to send quiz state to host")
+			// The host is not dropped, closing the connection ends the
+			// quiz as when the host disconnects.
+			e.HostConn.Close()
+		}
+	}
+
+	for _, c := range e.CoHosts {
+		if err := c.Conn.WriteJSON(hostPayload); err != nil {
+			log.Error().Err(err).Msg("Failed to send quiz state to co-host")
+			failed = append(failed, c.Conn)
+		}
+	}
+
+	if len(e.Spectators) > 0 {
+		spectatorPayload, err := e.getSpectatorPayload()
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to get spectator payload")
+			return err
+		}
+
+		for _, c := range e.Spectators {
+			if err := c.WriteJSON(spectatorPayload); err != nil {
+				log.Error().Err(err).Msg("Failed to send quiz state to spectator")
+				failed = append(failed, c)
+			}
+		}
 	}
 
 	for _, p := range e.Participants {
-		participantPayload, err := e.getParticipantPayload()
+		participantPayload, err := e.getParticipantPayload(p)
 		if err != nil {
 			log.Error().Err(err).Msg("Failed to get participant payload")
 			return err
 		}
 
 		if err := p.Conn.WriteJSON(participantPayload); err != nil {
-			log.Error().Err(err).Msg("Failed to send quiz state to participant")
-			return err
+			log.Error().Err(err).Str("participantId", p.ID).Msg("Failed to send quiz state to participant")
+			failed = append(failed, p.Conn)
 		}
 	}
 
+	for _, c := range failed {
+		e.removeConn(c)
+		c.Close()
+	}
+
 	return nil
 }
 
@@ -379,9 +1107,17 @@ func (e *Execution) getHostPayload() (interface{}, error) {
 	switch e.Phase {
 	case PhaseLobby:
 		return e.getHostLobbyPayload()
-	case PhaseQuestion:
+	case PhaseCountdown:
+		return e.getCountdownPayload()
+	case PhaseMedia, PhaseQuestion, PhasePaused:
 		return e.getHostQuestionPayload()
-	case PhaseResults:
+	case PhaseReveal:
+		return e.getHostRevealPayload()
+	case PhaseSlide:
+		return e.getSlidePayload()
+	case PhaseIntermission:
+		return e.getHostIntermissionPayload()
+	case PhaseResults, PhaseFinished:
 		return e.getHostResultsPayload()
 	default:
 		return nil, fmt.Errorf("unknown phase: %s", e.Phase)
@@ -390,127 +1126,217 @@ func (e *Execution) getHostPayload() (interface{}, error) {
 
 func (e *Execution) getHostLobbyPayload() (interface{}, error) {
 	payload := struct {
-		QuizTitle        string   `json:"quizTitle"`
-		HostName         string   `json:"hostName"`
-		IsHost           bool     `json:"isHost"`
-		ParticipantNames []string `json:"participantNames"`
-		Phase            string   `json:"phase"`
+		QuizTitle        string             `json:"quizTitle"`
+		HostName         string             `json:"hostName"`
+		IsHost           bool               `json:"isHost"`
+		ParticipantNames []string           `json:"participantNames"`
+		Participants     []lobbyParticipant `json:"participants"`
+		CoHostNames      []string           `json:"coHostNames"`
+		Teams            []lobbyTeam        `json:"teams,omitempty"`
+		// Muted holds the IDs of the participants whose reactions and chat
+		// messages are hidden.
+		Muted []string `json:"muted"`
+		Phase string   `json:"phase"`
 	}{
 		QuizTitle:        e.Quiz.Title,
 		HostName:         e.Host.Username,
 		IsHost:           true,
 		Phase:            string(e.Phase),
 		ParticipantNames: []string{},
+		Participants:     e.getLobbyParticipants(),
+		CoHostNames:      []string{},
+		Teams:            e.getLobbyTeams(),
+		Muted:            e.mutedParticipants(),
 	}
 
 	for _, p := range e.Participants {
 		payload.ParticipantNames = append(payload.ParticipantNames, p.Name)
 	}
 
+	for _, c := range e.CoHosts {
+		payload.CoHostNames = append(payload.CoHostNames, c.Name)
+	}
+
 	return payload, nil
 }
 
 func (e *Execution) getHostQuestionPayload() (interface{}, error) {
 	payload := struct {
-		Question string   `json:"question"`
-		Options  []string `json:"options"`
-		Phase    string   `json:"phase"`
+		Question    string   `json:"question"`
+		Options     []option `json:"options"`
+		Phase       string   `json:"phase"`
+		TimeLimit   uint64   `json:"timeLimit"`
+		IsPaused    bool     `json:"isPaused"`
+		AnswerCount int      `json:"answerCount"`
+		SuddenDeath bool     `json:"suddenDeath,omitempty"`
+		// Section is the title of the round the question is part of.
+		Section    string `json:"section,omitempty"`
+		Multiplier int    `json:"multiplier,omitempty"`
+		// Media is the video to play for the question. The answer options
+		// are locked while it plays.
+		Media    *mediaCue `json:"media,omitempty"`
+		ImageURL *string   `json:"imageUrl,omitempty"`
+		AudioURL *string   `json:"audioUrl,omitempty"`
 	}{
-		Phase: string(e.Phase),
+		Phase:     string(e.Phase),
+		TimeLimit: e.timeLeft(),
+		IsPaused:  e.Phase == PhasePaused,
 	}
 
 	q := e.Questions[e.CurrentQuestion]
-	payload.Options = q.Answers
+	payload.SuddenDeath = e.Tiebreaks[q.ID]
+	if s, ok := e.section(q); ok {
+		payload.Section = s.Title
+		payload.Multiplier = s.Factor()
+	}
+	if cue, ok := getMediaCue(q); ok {
+		payload.Media = &cue
+	}
+	payload.Options = toOptions(q.Options)
 	payload.Question = q.Question
+	payload.ImageURL = q.ImageURL
+	payload.AudioURL = q.AudioURL
+	for _, p := range e.Participants {
+		if _, ok := p.Answers[q.ID]; ok {
+			payload.AnswerCount++
+		}
+	}
 
 	return payload, nil
 }
 
 func (e *Execution) getHostResultsPayload() (interface{}, error) {
 	payload := struct {
-		Phase                string `json:"phase"`
-		NrQuestionsCompleted int    `json:"nrQuestionsCompleted"`
-		TotalQuestions       int    `json:"totalQuestions"`
-		Results              []struct {
-			Name      string `json:"name"`
-			NrCorrect int    `json:"nrCorrect"`
-		} `json:"results"`
+		Phase                string              `json:"phase"`
+		NrQuestionsCompleted int                 `json:"nrQuestionsCompleted"`
+		TotalQuestions       int                 `json:"totalQuestions"`
+		SkippedQuestions     []string            `json:"skippedQuestions"`
+		Results              []participantResult `json:"results"`
+		TeamResults          []teamResult        `json:"teamResults,omitempty"`
+		// Podium holds the top of the leaderboard once the quiz has
+		// finished.
+		Podium []participantResult `json:"podium,omitempty"`
+		// TimeLeft is how long the results are shown for on auto-pilot.
+		TimeLeft uint64 `json:"timeLeft,omitempty"`
 	}{
 		Phase:                string(e.Phase),
 		NrQuestionsCompleted: e.CurrentQuestion,
 		TotalQuestions:       len(e.Questions),
+		SkippedQuestions:     []string{},
+		Results:              e.getResults(),
+		TeamResults:          e.getTeamResults(),
+		TimeLeft:             e.phaseTimeLeft(),
 	}
 
-	for _, p := range e.Participants {
-		nrCorrect := 0
-		for i, q := range e.Questions {
-			if i >= e.CurrentQuestion {
-				break
-			}
+	if e.Phase == PhaseFinished {
+		payload.Podium = payload.Results[:min(podiumSize, len(payload.Results))]
+	}
 
-			answer, ok := p.Answers[q.ID]
-			if !ok {
-				continue
-			}
-			if slices.Contains(q.CorrectAnswers, answer) {
-				nrCorrect++
-			}
+	for i, q := range e.Questions {
+		if i < e.CurrentQuestion && e.SkippedQuestions[q.ID] {
+			payload.SkippedQuestions = append(payload.SkippedQuestions, q.ID)
 		}
-
-		payload.Results = append(payload.Results, struct {
-			Name      string `json:"name"`
-			NrCorrect int    `json:"nrCorrect"`
-		}{
-			Name:      p.Name,
-			NrCorrect: nrCorrect,
-		})
 	}
 
 	return payload, nil
 }
 
-func (e *Execution) getParticipantPayload() (interface{}, error) {
+func (e *Execution) getParticipantPayload(p Participant) (interface{}, error) {
+	// Eliminated participants watch the remaining questions as spectators.
+	if p.Eliminated && (e.Phase == PhaseCountdown || e.Phase == PhaseMedia || e.Phase == PhaseQuestion || e.Phase == PhasePaused) {
+		return e.getSpectatorPayload()
+	}
+
 	switch e.Phase {
 	case PhaseLobby:
-		return e.getParticipantLobbyPayload()
+		return e.getParticipantLobbyPayload(p)
+	case PhaseCountdown:
+		return e.getCountdownPayload()
+	case PhaseMedia:
+		return e.getParticipantMediaPayload()
 	case PhaseQuestion:
-		return e.getParticipantQuestionPayload()
-	case PhaseResults:
-		return e.getParticipantResultsPayload()
+		return e.getParticipantQuestionPayload(p)
+	case PhasePaused:
+		return e.getParticipantPausedPayload()
+	case PhaseReveal:
+		return e.getParticipantRevealPayload(p)
+	case PhaseSlide:
+		return e.getSlidePayload()
+	case PhaseIntermission:
+		return e.getParticipantIntermissionPayload(p)
+	case PhaseResults, PhaseFinished:
+		return e.getParticipantResultsPayload(p)
 	default:
 		return nil, fmt.Errorf("unknown phase: %s", e.Phase)
 	}
 }
 
-func (e *Execution) getParticipantLobbyPayload() (interface{}, error) {
-	return struct {
-		QuizTitle string `json:"quizTitle"`
-		HostName  string `json:"hostName"`
-		IsHost    bool   `json:"isHost"`
-		Phase     string `json:"phase"`
+func (e *Execution) getParticipantLobbyPayload(p Participant) (interface{}, error) {
+	payload := struct {
+		QuizTitle string   `json:"quizTitle"`
+		HostName  string   `json:"hostName"`
+		IsHost    bool     `json:"isHost"`
+		Avatar    string   `json:"avatar"`
+		Color     string   `json:"color"`
+		Avatars   []string `json:"avatars"`
+		Colors    []string `json:"colors"`
+		Team      string   `json:"team,omitempty"`
+		Teams     []string `json:"teams,omitempty"`
+		// Reactions are the emoji the participant can react with, if
+		// reactions are enabled.
+		Reactions []string `json:"reactions,omitempty"`
+		Chat      bool     `json:"chat"`
+		Phase     string   `json:"phase"`
 	}{
 		QuizTitle: e.Quiz.Title,
 		HostName:  e.Host.Username,
 		IsHost:    false,
+		Avatar:    p.Avatar,
+		Color:     p.Color,
+		Avatars:   Avatars,
+		Colors:    Colors,
+		Team:      p.Team,
+		Teams:     e.Settings.Teams,
+		Chat:      e.Settings.Chat,
 		Phase:     string(e.Phase),
-	}, nil
+	}
+	if e.Settings.Reactions {
+		payload.Reactions = Reactions
+	}
+
+	return payload, nil
 }
 
-func (e *Execution) getParticipantQuestionPayload() (interface{}, error) {
+func (e *Execution) getParticipantQuestionPayload(p Participant) (interface{}, error) {
 	payload := struct {
-		Options []string `json:"options"`
-		Phase   string   `json:"phase"`
+		Options     []option `json:"options"`
+		Phase       string   `json:"phase"`
+		SuddenDeath bool     `json:"suddenDeath,omitempty"`
+		// StartedAt is when the answer options were unlocked and ServerTime
+		// is when the state was sent, both in milliseconds since the epoch
+		// on the clock of the server.
+		StartedAt  int64 `json:"startedAt"`
+		ServerTime int64 `json:"serverTime"`
+		// TeamVotes is how many team members chose each option, so that a
+		// huddling team can settle on an answer.
+		TeamVotes map[string]int `json:"teamVotes,omitempty"`
 	}{
-		Phase: string(e.Phase),
+		Phase:      string(e.Phase),
+		StartedAt:  e.QuestionStartedAt.UnixMilli(),
+		ServerTime: time.Now().UnixMilli(),
 	}
 
 	q := e.Questions[e.CurrentQuestion]
-	payload.Options = q.Answers
+	payload.Options = e.participantOptions(p.ID, q)
+	payload.SuddenDeath = e.Tiebreaks[q.ID]
+	if e.Settings.TeamHuddle && p.Team != "" {
+		payload.TeamVotes = e.teamVotes(q, p.Team)
+	}
 
 	return payload, nil
 }
 
-func (e *Execution) getParticipantResultsPayload() (interface{}, error) {
+func (e *Execution) getParticipantPausedPayload() (interface{}, error) {
 	payload := struct {
 		Phase string `json:"phase"`
 	}{
@@ -519,10 +1345,72 @@ func (e *Execution) getParticipantResultsPayload() (interface{}, error) {
 	return payload, nil
 }
 
+func (e *Execution) getParticipantResultsPayload(p Participant) (interface{}, error) {
+	payload := struct {
+		Phase          string   `json:"phase"`
+		TotalScore     int      `json:"totalScore"`
+		Answer         *string  `json:"answer,omitempty"`
+		IsCorrect      *bool    `json:"isCorrect,omitempty"`
+		CorrectOptions []option `json:"correctOptions,omitempty"`
+		Team           string   `json:"team,omitempty"`
+		TeamRank       int      `json:"teamRank,omitempty"`
+		TeamAnswer     *string  `json:"teamAnswer,omitempty"`
+		Eliminated     bool     `json:"eliminated,omitempty"`
+		IsWinner       bool     `json:"isWinner,omitempty"`
+		// Rank is the final place of the participant once the quiz has
+		// finished.
+		Rank int `json:"rank,omitempty"`
+	}{
+		Phase:      string(e.Phase),
+		Team:       p.Team,
+		Eliminated: p.Eliminated,
+		IsWinner:   e.Settings.Elimination && e.Phase == PhaseFinished && !p.Eliminated,
+	}
+
+	if p.Team != "" {
+		payload.TeamRank = e.teamRank(p.Team)
+	}
+
+	if e.Phase == PhaseFinished {
+		payload.Rank = e.rank(p)
+	}
+
+	for _, score := range p.Scores {
+		payload.TotalScore += score
+	}
+
+	if e.Settings.ShowCorrectAnswers && e.CurrentQuestion > 0 && !e.SkippedQuestions[e.Questions[e.CurrentQuestion-1].ID] {
+		q := e.Questions[e.CurrentQuestion-1]
+		payload.CorrectOptions = toOptions(q.CorrectOptions())
+		if answer, ok := p.Answers[q.ID]; ok {
+			isCorrect := q.IsCorrect(answer)
+			payload.Answer = &answer
+			payload.IsCorrect = &isCorrect
+		}
+		if e.Settings.TeamHuddle && p.Team != "" {
+			if answer, _, ok := e.huddleAnswer(q, p.Team); ok {
+				payload.TeamAnswer = &answer
+			}
+		}
+	}
+
+	return payload, nil
+}
+
 func (e *Execution) getParticipant(participantId string) (*Participant, bool) {
-	for _, p := range e.Participants {
-		if p.ID == participantId {
-			return &p, true
+	for i := range e.Participants {
+		if e.Participants[i].ID == participantId {
+			return &e.Participants[i], true
+		}
+	}
+
+	return nil, false
+}
+
+func (e *Execution) getParticipantByConn(conn Conn) (*Participant, bool) {
+	for i := range e.Participants {
+		if e.Participants[i].Conn == conn {
+			return &e.Participants[i], true
 		}
 	}
 
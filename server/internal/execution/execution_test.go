@@ -0,0 +1,298 @@
+package execution
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+	"github.com/william-joh/quizzer/server/internal/quizzer"
+)
+
+func newTestExecution(settings Settings, questions ...quizzer.Question) *Execution {
+	return &Execution{
+		Settings:         settings,
+		Phase:            PhaseLobby,
+		CreatedAt:        time.Now(),
+		SkippedQuestions: map[string]bool{},
+		Tiebreaks:        map[string]bool{},
+		unsavedOptions:   map[string]bool{},
+		done:             make(chan bool, 1),
+		Host:             quizzer.User{ID: "host-id", Username: "host"},
+		Questions:        questions,
+	}
+}
+
+// serveTestExecution serves the execution over websockets, the way the API
+// does. Connections are identified by the id query parameter.
+func serveTestExecution(t *testing.T, e *Execution) string {
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		defer e.Disconnect(conn)
+
+		id := r.URL.Query().Get("id")
+		identity := Identity{ID: id, Name: id, IsGuest: id != e.Host.ID}
+		for {
+			if err := e.HandleMessages(conn, identity); errors.Is(err, ErrClosed) {
+				return
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+func dialTestExecution(t *testing.T, url, id string) *websocket.Conn {
+	conn, _, err := websocket.DefaultDialer.Dial(url+"?id="+id, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	require.NoError(t, conn.WriteJSON(Message{Type: "Join", Data: map[string]any{"username": id}}))
+	return conn
+}
+
+// readUntil reads messages from the connection until one matches.
+func readUntil(t *testing.T, conn *websocket.Conn, match func(msg map[string]any) bool) map[string]any {
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	for {
+		var msg map[string]any
+		require.NoError(t, conn.ReadJSON(&msg))
+		if match(msg) {
+			return msg
+		}
+	}
+}
+
+// testConn is a connection that keeps what is sent to it.
+type testConn struct {
+	sent   []any
+	closed bool
+}
+
+func (c *testConn) WriteJSON(v any) error {
+	c.sent = append(c.sent, v)
+	return nil
+}
+
+func (c *testConn) Close() error {
+	c.closed = true
+	return nil
+}
+
+func phase(p Phase) func(map[string]any) bool {
+	return func(msg map[string]any) bool {
+		return msg["phase"] == string(p)
+	}
+}
+
+func TestHandleMessages(t *testing.T) {
+	t.Run("answering twice keeps the participant connected", func(t *testing.T) {
+		e := newTestExecution(
+			Settings{ScoringMode: ScoringModeSpeed},
+			quizzer.Question{ID: "q1", Question: "q1", TimeLimitSeconds: 20, Options: []quizzer.AnswerOption{
+				{ID: "o1", Text: "a", IsCorrect: true},
+				{ID: "o2", Text: "b"},
+			}},
+		)
+		e.Run()
+		url := serveTestExecution(t, e)
+
+		host := dialTestExecution(t, url, e.Host.ID)
+		readUntil(t, host, phase(PhaseLobby))
+		p1 := dialTestExecution(t, url, "p1")
+		readUntil(t, p1, phase(PhaseLobby))
+		p2 := dialTestExecution(t, url, "p2")
+		readUntil(t, p2, phase(PhaseLobby))
+
+		require.NoError(t, host.WriteJSON(Message{Type: "Start"}))
+		readUntil(t, p1, phase(PhaseQuestion))
+
+		answer := Message{Type: "AnswerQuestion", Data: map[string]any{"optionId": "o1"}}
+		require.NoError(t, p1.WriteJSON(answer))
+		readUntil(t, p1, phase(PhaseQuestion))
+		require.NoError(t, p1.WriteJSON(answer))
+
+		msg := readUntil(t, p1, func(msg map[string]any) bool { return msg["type"] == "error" })
+		require.Contains(t, msg["error"], "already answered")
+
+		// The rejected answer did not drop the participant, so the question
+		// ends when the other participant answers too.
+		require.NoError(t, p2.WriteJSON(answer))
+		readUntil(t, p1, phase(PhaseResults))
+
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		require.Len(t, e.Participants, 2)
+	})
+}
+
+func TestHandleJoin(t *testing.T) {
+	tests := []struct {
+		name     string
+		identity Identity
+		data     map[string]any
+		err      error
+	}{
+		{
+			name:     "participant with password",
+			identity: Identity{ID: "p1", Name: "p1", IsGuest: true},
+			data:     map[string]any{"password": "secret"},
+		},
+		{
+			name:     "participant with wrong password",
+			identity: Identity{ID: "p1", Name: "p1", IsGuest: true},
+			data:     map[string]any{"password": "guess"},
+			err:      ErrWrongPassword,
+		},
+		{
+			name:     "participant without password",
+			identity: Identity{ID: "p1", Name: "p1", IsGuest: true},
+			err:      ErrWrongPassword,
+		},
+		{
+			name: "spectator with password",
+			data: map[string]any{"role": RoleSpectator, "password": "secret"},
+		},
+		{
+			name: "spectator with wrong password",
+			data: map[string]any{"role": RoleSpectator, "password": "guess"},
+			err:  ErrWrongPassword,
+		},
+		{
+			name: "spectator without password",
+			data: map[string]any{"role": RoleSpectator},
+			err:  ErrWrongPassword,
+		},
+		{
+			name:     "host without password",
+			identity: Identity{ID: "host-id", Name: "host"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestExecution(Settings{JoinPassword: "secret"})
+			conn := &testConn{}
+
+			err := e.HandleMessage(conn, tt.identity, Message{Type: "Join", Data: tt.data})
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+				require.False(t, e.isJoined(conn))
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, e.isJoined(conn))
+		})
+	}
+}
+
+func TestQuestionControls(t *testing.T) {
+	questions := testQuestions(2, 2)
+	for i := range questions {
+		questions[i].TimeLimitSeconds = 20
+	}
+	host := Identity{ID: "host-id"}
+	p1 := Identity{ID: "p1", Name: "p1", IsGuest: true}
+	p2 := Identity{ID: "p2", Name: "p2", IsGuest: true}
+
+	// start returns a started execution, with the first participant having
+	// answered the first question correctly, and the connections of the
+	// host and the participants.
+	start := func(t *testing.T) (*Execution, map[string]*testConn) {
+		e := newTestExecution(Settings{TimerEnabled: true}, questions...)
+		conns := map[string]*testConn{"host-id": {}, "p1": {}, "p2": {}}
+		for _, identity := range []Identity{host, p1, p2} {
+			require.NoError(t, e.HandleMessage(conns[identity.ID], identity, Message{Type: "Join"}))
+		}
+		require.NoError(t, e.HandleMessage(conns["host-id"], host, Message{Type: "Start"}))
+		require.NoError(t, e.HandleMessage(conns["p1"], p1, Message{Type: "AnswerQuestion", Data: map[string]any{"optionId": "q0-o0"}}))
+		return e, conns
+	}
+	send := func(t *testing.T, e *Execution, conns map[string]*testConn, identity Identity, msgType string) {
+		require.NoError(t, e.HandleMessage(conns[identity.ID], identity, Message{Type: msgType}))
+	}
+
+	t.Run("pause keeps the time left", func(t *testing.T) {
+		e, conns := start(t)
+		// Eight seconds of the question have passed.
+		e.QuestionDeadline = time.Now().Add(12 * time.Second)
+
+		send(t, e, conns, host, "PauseQuestion")
+		require.Equal(t, PhasePaused, e.Phase)
+		require.True(t, e.QuestionDeadline.IsZero())
+		require.InDelta(t, 12*time.Second, e.remaining, float64(100*time.Millisecond))
+		require.Equal(t, uint64(12), e.timeLeft())
+
+		time.Sleep(1100 * time.Millisecond)
+		require.Equal(t, uint64(12), e.timeLeft(), "time must not run while paused")
+	})
+
+	t.Run("resume restores the time left", func(t *testing.T) {
+		e, conns := start(t)
+		e.QuestionDeadline = time.Now().Add(12 * time.Second)
+		send(t, e, conns, host, "PauseQuestion")
+
+		send(t, e, conns, host, "ResumeQuestion")
+		require.Equal(t, PhaseQuestion, e.Phase)
+		require.Zero(t, e.remaining)
+		require.WithinDuration(t, time.Now().Add(12*time.Second), e.QuestionDeadline, 100*time.Millisecond)
+
+		// The answer given before the pause still counts.
+		send(t, e, conns, host, "FinishQuestion")
+		p, ok := e.getParticipant("p1")
+		require.True(t, ok)
+		total, _ := e.totalScore(*p)
+		require.Equal(t, 1, total)
+	})
+
+	t.Run("skipped question is not scored", func(t *testing.T) {
+		e, conns := start(t)
+
+		send(t, e, conns, host, "SkipQuestion")
+		require.Equal(t, PhaseResults, e.Phase)
+		require.True(t, e.SkippedQuestions["q0"])
+		p, ok := e.getParticipant("p1")
+		require.True(t, ok)
+		total, nrCorrect := e.totalScore(*p)
+		require.Zero(t, total)
+		require.Zero(t, nrCorrect)
+
+		send(t, e, conns, host, "NextQuestion")
+		require.NoError(t, e.HandleMessage(conns["p1"], p1, Message{Type: "AnswerQuestion", Data: map[string]any{"optionId": "q1-o0"}}))
+		send(t, e, conns, host, "FinishQuestion")
+		total, nrCorrect = e.totalScore(*p)
+		require.Equal(t, 1, total)
+		require.Equal(t, 1, nrCorrect)
+	})
+
+	t.Run("reopen clears the answers", func(t *testing.T) {
+		e, conns := start(t)
+		send(t, e, conns, host, "FinishQuestion")
+		p, ok := e.getParticipant("p1")
+		require.True(t, ok)
+		total, _ := e.totalScore(*p)
+		require.Equal(t, 1, total)
+
+		send(t, e, conns, host, "ReopenQuestion")
+		require.Equal(t, PhaseQuestion, e.Phase)
+		require.Zero(t, e.CurrentQuestion)
+		require.Empty(t, p.Answers)
+		require.Empty(t, p.Scores)
+
+		// The participant answers again, this time wrongly.
+		require.NoError(t, e.HandleMessage(conns["p1"], p1, Message{Type: "AnswerQuestion", Data: map[string]any{"optionId": "q0-o1"}}))
+		send(t, e, conns, host, "FinishQuestion")
+		require.Equal(t, "q0-o1", p.Answers["q0"])
+		total, _ = e.totalScore(*p)
+		require.Zero(t, total)
+	})
+}
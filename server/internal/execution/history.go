@@ -0,0 +1,115 @@
+package execution
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"github.com/william-joh/quizzer/server/internal/postgres"
+	"github.com/william-joh/quizzer/server/internal/quizzer"
+)
+
+// saveGame stores the results of the execution in the game history, so that
+// the host can review them after the execution has been cleaned up.
+func (s *inMemoryService) saveGame(ctx context.Context, e *Execution) error {
+	e.mu.Lock()
+	if e.CurrentQuestion == 0 {
+		// No question was finished, so there are no results to keep.
+		e.mu.Unlock()
+		return nil
+	}
+	game, participants, answers, err := e.history(time.Now())
+	e.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	log.Debug().Str("code", e.Code).Str("gameID", game.ID).Msg("Saving game")
+	return s.db.InTx(ctx, func(s postgres.Session) error {
+		if err := s.CreateGame(ctx, game); err != nil {
+			return fmt.Errorf("create game: %w", err)
+		}
+
+		for _, p := range participants {
+			if err := s.CreateGameParticipant(ctx, p); err != nil {
+				return fmt.Errorf("create game participant: %w", err)
+			}
+		}
+
+		for _, a := range answers {
+			if err := s.SaveGameAnswer(ctx, a); err != nil {
+				return fmt.Errorf("save game answer: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// history returns the execution as it is stored in the game history, ended at
+// the given time. Only finished questions that were not skipped are included,
+// sudden death questions are left out as they are not questions of the quiz.
+func (e *Execution) history(endedAt time.Time) (quizzer.Game, []quizzer.GameParticipant, []quizzer.GameAnswer, error) {
+	settings := e.Settings
+	settings.JoinPassword = ""
+	settingsJSON, err := json.Marshal(settings)
+	if err != nil {
+		return quizzer.Game{}, nil, nil, fmt.Errorf("encode settings: %w", err)
+	}
+
+	game := quizzer.Game{
+		ID:       uuid.New().String(),
+		QuizID:   e.Quiz.ID,
+		HostID:   e.Host.ID,
+		Mode:     quizzer.GameModeLive,
+		Settings: settingsJSON,
+		OpensAt:  e.CreatedAt,
+		ClosesAt: endedAt,
+	}
+
+	participants := []quizzer.GameParticipant{}
+	answers := []quizzer.GameAnswer{}
+	for _, p := range e.allParticipants() {
+		participant := quizzer.GameParticipant{
+			ID:         uuid.New().String(),
+			GameID:     game.ID,
+			UserID:     p.ID,
+			Name:       p.Name,
+			Avatar:     p.Avatar,
+			Color:      p.Color,
+			IsGuest:    p.IsGuest,
+			StartedAt:  e.CreatedAt,
+			FinishedAt: &endedAt,
+		}
+
+		for _, q := range e.Questions[:e.CurrentQuestion] {
+			if e.SkippedQuestions[q.ID] || e.Tiebreaks[q.ID] {
+				continue
+			}
+
+			answer := quizzer.GameAnswer{
+				ParticipantID: participant.ID,
+				QuestionID:    q.ID,
+				Score:         p.Scores[q.ID],
+			}
+			if optionID, ok := p.Answers[q.ID]; ok {
+				answeredAt := p.answeredAt[q.ID]
+				if !e.unsavedOptions[optionID] {
+					answer.OptionID = &optionID
+				}
+				answer.IsCorrect = q.IsCorrect(optionID)
+				answer.AnsweredAt = &answeredAt
+			}
+
+			participant.Score += answer.Score
+			answers = append(answers, answer)
+		}
+
+		participants = append(participants, participant)
+	}
+
+	return game, participants, answers, nil
+}
@@ -0,0 +1,74 @@
+package execution
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/william-joh/quizzer/server/internal/quizzer"
+)
+
+func TestHistory(t *testing.T) {
+	question := quizzer.Question{ID: "q1", Question: "q1", TimeLimitSeconds: 20, Options: []quizzer.AnswerOption{
+		{ID: "o1", Text: "a", IsCorrect: true},
+		{ID: "o2", Text: "b"},
+	}}
+	p1 := Identity{ID: "p1", Name: "p1", IsGuest: true}
+	p2 := Identity{ID: "p2", Name: "p2", IsGuest: true}
+
+	// play joins two participants and answers the question with the first,
+	// which then leaves.
+	play := func(t *testing.T) (*Execution, *testConn) {
+		e := newTestExecution(Settings{ScoringMode: ScoringModeSpeed}, question)
+		host, c1, c2 := &testConn{}, &testConn{}, &testConn{}
+		require.NoError(t, e.HandleMessage(host, Identity{ID: e.Host.ID}, Message{Type: "Join"}))
+		require.NoError(t, e.HandleMessage(c1, p1, Message{Type: "Join"}))
+		require.NoError(t, e.HandleMessage(c2, p2, Message{Type: "Join"}))
+		require.NoError(t, e.HandleMessage(host, Identity{ID: e.Host.ID}, Message{Type: "Start"}))
+		require.NoError(t, e.HandleMessage(c1, p1, Message{Type: "AnswerQuestion", Data: map[string]any{"optionId": "o1"}}))
+		require.NoError(t, e.Disconnect(c1))
+		require.NoError(t, e.HandleMessage(host, Identity{ID: e.Host.ID}, Message{Type: "FinishQuestion"}))
+		return e, c1
+	}
+
+	t.Run("participants that left are kept", func(t *testing.T) {
+		e, _ := play(t)
+		require.Len(t, e.Participants, 1)
+
+		_, participants, answers, err := e.history(time.Now())
+		require.NoError(t, err)
+		require.Len(t, participants, 2)
+		require.Len(t, answers, 2)
+
+		scores := map[string]int{}
+		for _, p := range participants {
+			scores[p.UserID] = p.Score
+		}
+		require.Positive(t, scores["p1"])
+		require.Zero(t, scores["p2"])
+	})
+
+	t.Run("participants that come back keep their results", func(t *testing.T) {
+		e, _ := play(t)
+		c1 := &testConn{}
+		require.NoError(t, e.HandleMessage(c1, p1, Message{Type: "Join"}))
+		require.Len(t, e.Participants, 2)
+		require.Empty(t, e.departed)
+
+		p, ok := e.getParticipantByConn(c1)
+		require.True(t, ok)
+		require.Equal(t, "o1", p.Answers[question.ID])
+
+		_, participants, _, err := e.history(time.Now())
+		require.NoError(t, err)
+		require.Len(t, participants, 2)
+	})
+
+	t.Run("participants that leave the lobby are not kept", func(t *testing.T) {
+		e := newTestExecution(Settings{}, question)
+		c1 := &testConn{}
+		require.NoError(t, e.HandleMessage(c1, p1, Message{Type: "Join"}))
+		require.NoError(t, e.Disconnect(c1))
+		require.Empty(t, e.allParticipants())
+	})
+}
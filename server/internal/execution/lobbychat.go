@@ -0,0 +1,181 @@
+package execution
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Reactions are the emoji participants can react with in the lobby.
+var Reactions = []string{"👍", "👏", "😂", "😮", "🎉", "🔥", "❤️"}
+
+const (
+	// maxChatLength is the number of characters a chat message can have.
+	maxChatLength = 100
+	// reactionInterval is how often a participant can react.
+	reactionInterval = 500 * time.Millisecond
+	// chatInterval is how often a participant can send a chat message.
+	chatInterval = 3 * time.Second
+)
+
+// ErrRateLimited is returned when a participant sends reactions or chat
+// messages faster than allowed.
+var ErrRateLimited = errors.New("sending too fast")
+
+// lobbyEvent is a reaction or chat message, as it is shown on the host screen.
+type lobbyEvent struct {
+	Type   string `json:"type"`
+	UserID string `json:"userId"`
+	Name   string `json:"name"`
+	Emoji  string `json:"emoji,omitempty"`
+	Text   string `json:"text,omitempty"`
+}
+
+// lobbySender returns the participant on the connection, if they may send
+// reactions and chat messages at the moment.
+func (e *Execution) lobbySender(conn Conn) (*Participant, error) {
+	p, ok := e.getParticipantByConn(conn)
+	if !ok {
+		log.Error().Msg("Only participants can send to the lobby")
+		return nil, fmt.Errorf("only participants can send to the lobby")
+	}
+
+	if e.Phase != PhaseLobby {
+		log.Error().Msg("Not in lobby phase")
+		return nil, fmt.Errorf("not in lobby phase")
+	}
+
+	return p, nil
+}
+
+func (e *Execution) handleReactMsg(conn Conn, msg Message) error {
+	p, err := e.lobbySender(conn)
+	if err != nil {
+		return err
+	}
+
+	if !e.Settings.Reactions {
+		log.Error().Msg("Reactions are disabled")
+		return fmt.Errorf("reactions are disabled")
+	}
+
+	data, _ := msg.Data.(map[string]interface{})
+	emoji, _ := data["emoji"].(string)
+	if !slices.Contains(Reactions, emoji) {
+		log.Error().Str("emoji", emoji).Msg("Unknown reaction")
+		return fmt.Errorf("unknown reaction: %s", emoji)
+	}
+
+	if time.Since(p.lastReactionAt) < reactionInterval {
+		return ErrRateLimited
+	}
+	p.lastReactionAt = time.Now()
+
+	// Muted participants are not told, so that they do not try to get around
+	// it.
+	if p.Muted {
+		return nil
+	}
+
+	return e.sendToHostScreens(lobbyEvent{Type: "reaction", UserID: p.ID, Name: p.Name, Emoji: emoji})
+}
+
+func (e *Execution) handleChatMsg(conn Conn, msg Message) error {
+	p, err := e.lobbySender(conn)
+	if err != nil {
+		return err
+	}
+
+	if !e.Settings.Chat {
+		log.Error().Msg("Chat is disabled")
+		return fmt.Errorf("chat is disabled")
+	}
+
+	data, _ := msg.Data.(map[string]interface{})
+	text, _ := data["text"].(string)
+	text = strings.TrimSpace(text)
+	if text == "" {
+		log.Error().Msg("Chat message is empty")
+		return fmt.Errorf("chat message is empty")
+	}
+	if utf8.RuneCountInString(text) > maxChatLength {
+		log.Error().Msg("Chat message is too long")
+		return fmt.Errorf("chat message cannot be longer than %d characters", maxChatLength)
+	}
+
+	if time.Since(p.lastChatAt) < chatInterval {
+		return ErrRateLimited
+	}
+	p.lastChatAt = time.Now()
+
+	if p.Muted {
+		return nil
+	}
+
+	return e.sendToHostScreens(lobbyEvent{Type: "chat", UserID: p.ID, Name: p.Name, Text: text})
+}
+
+func (e *Execution) handleMuteMsg(conn Conn, msg Message, muted bool) error {
+	if !e.canControl(conn) {
+		log.Error().Msg("Only a host can mute participants")
+		return fmt.Errorf("only a host can mute participants")
+	}
+
+	userID, err := userIDFromMsg(msg)
+	if err != nil {
+		return err
+	}
+
+	p, ok := e.getParticipant(userID)
+	if !ok {
+		log.Error().Str("userId", userID).Msg("Participant not found")
+		return fmt.Errorf("participant %s not found", userID)
+	}
+	p.Muted = muted
+
+	// Broadcast the new quiz state
+	if err := e.broadcastQuizState(); err != nil {
+		log.Error().Err(err).Msg("Failed to broadcast quiz state")
+		return fmt.Errorf("broadcast quiz state: %w", err)
+	}
+
+	return nil
+}
+
+// sendToHostScreens sends the event to the host, the co-hosts and the
+// spectators.
+func (e *Execution) sendToHostScreens(event lobbyEvent) error {
+	conns := slices.Clone(e.Spectators)
+	if e.HostConn != nil {
+		conns = append(conns, e.HostConn)
+	}
+	for _, c := range e.CoHosts {
+		conns = append(conns, c.Conn)
+	}
+
+	for _, c := range conns {
+		if err := c.WriteJSON(event); err != nil {
+			log.Error().Err(err).Msg("Failed to send lobby event")
+			return fmt.Errorf("send lobby event: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// mutedParticipants returns the IDs of the participants whose reactions and
+// chat messages are not shown.
+func (e *Execution) mutedParticipants() []string {
+	muted := []string{}
+	for _, p := range e.Participants {
+		if p.Muted {
+			muted = append(muted, p.ID)
+		}
+	}
+	return muted
+}
@@ -0,0 +1,203 @@
+package execution
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLobbyChat(t *testing.T) {
+	react := func(emoji string) Message {
+		return Message{Type: "React", Data: map[string]any{"emoji": emoji}}
+	}
+	chat := func(text string) Message {
+		return Message{Type: "Chat", Data: map[string]any{"text": text}}
+	}
+	mute := Message{Type: "Mute", Data: map[string]any{"userId": "p1"}}
+	unmute := Message{Type: "Unmute", Data: map[string]any{"userId": "p1"}}
+	reaction := lobbyEvent{Type: "reaction", UserID: "p1", Name: "p1", Emoji: "👍"}
+	message := lobbyEvent{Type: "chat", UserID: "p1", Name: "p1", Text: "hi"}
+
+	type step struct {
+		// from is who sends the message, the host or p1.
+		from string
+		msg  Message
+		// wait lets the participant send again without being rate
+		// limited.
+		wait bool
+		err  string
+	}
+
+	tests := []struct {
+		name     string
+		settings Settings
+		started  bool
+		steps    []step
+		want     []lobbyEvent
+		muted    bool
+	}{
+		{
+			name:     "reaction",
+			settings: Settings{Reactions: true},
+			steps:    []step{{from: "p1", msg: react("👍")}},
+			want:     []lobbyEvent{reaction},
+		},
+		{
+			name:     "unknown reaction",
+			settings: Settings{Reactions: true},
+			steps:    []step{{from: "p1", msg: react("💩"), err: "unknown reaction"}},
+			want:     []lobbyEvent{},
+		},
+		{
+			name:  "reactions disabled",
+			steps: []step{{from: "p1", msg: react("👍"), err: "reactions are disabled"}},
+			want:  []lobbyEvent{},
+		},
+		{
+			name:     "reactions too fast",
+			settings: Settings{Reactions: true},
+			steps: []step{
+				{from: "p1", msg: react("👍")},
+				{from: "p1", msg: react("👍"), err: ErrRateLimited.Error()},
+				{from: "p1", msg: react("👍"), wait: true},
+			},
+			want: []lobbyEvent{reaction, reaction},
+		},
+		{
+			name:     "chat",
+			settings: Settings{Chat: true},
+			steps:    []step{{from: "p1", msg: chat("  hi ")}},
+			want:     []lobbyEvent{message},
+		},
+		{
+			name:     "empty chat message",
+			settings: Settings{Chat: true},
+			steps:    []step{{from: "p1", msg: chat("  "), err: "chat message is empty"}},
+			want:     []lobbyEvent{},
+		},
+		{
+			name:     "chat message too long",
+			settings: Settings{Chat: true},
+			steps:    []step{{from: "p1", msg: chat(strings.Repeat("a", maxChatLength+1)), err: "cannot be longer"}},
+			want:     []lobbyEvent{},
+		},
+		{
+			name:     "chat message of the longest length in emoji",
+			settings: Settings{Chat: true},
+			steps:    []step{{from: "p1", msg: chat(strings.Repeat("🎉", maxChatLength))}},
+			want:     []lobbyEvent{{Type: "chat", UserID: "p1", Name: "p1", Text: strings.Repeat("🎉", maxChatLength)}},
+		},
+		{
+			name:  "chat disabled",
+			steps: []step{{from: "p1", msg: chat("hi"), err: "chat is disabled"}},
+			want:  []lobbyEvent{},
+		},
+		{
+			name:     "chat too fast",
+			settings: Settings{Chat: true},
+			steps: []step{
+				{from: "p1", msg: chat("hi")},
+				{from: "p1", msg: chat("hi"), err: ErrRateLimited.Error()},
+				{from: "p1", msg: chat("hi"), wait: true},
+			},
+			want: []lobbyEvent{message, message},
+		},
+		{
+			name:     "muted",
+			settings: Settings{Reactions: true, Chat: true},
+			steps: []step{
+				{from: "host", msg: mute},
+				{from: "p1", msg: react("👍")},
+				{from: "p1", msg: chat("hi")},
+			},
+			want:  []lobbyEvent{},
+			muted: true,
+		},
+		{
+			name:     "muted participants are still rate limited",
+			settings: Settings{Chat: true},
+			steps: []step{
+				{from: "host", msg: mute},
+				{from: "p1", msg: chat("hi")},
+				{from: "p1", msg: chat("hi"), err: ErrRateLimited.Error()},
+			},
+			want:  []lobbyEvent{},
+			muted: true,
+		},
+		{
+			name:     "unmuted",
+			settings: Settings{Chat: true},
+			steps: []step{
+				{from: "host", msg: mute},
+				{from: "p1", msg: chat("hi")},
+				{from: "host", msg: unmute},
+				{from: "p1", msg: chat("hi"), wait: true},
+			},
+			want: []lobbyEvent{message},
+		},
+		{
+			name:     "participants cannot mute",
+			settings: Settings{Chat: true},
+			steps:    []step{{from: "p1", msg: mute, err: "only a host can mute participants"}},
+			want:     []lobbyEvent{},
+		},
+		{
+			name:     "after the quiz started",
+			settings: Settings{Reactions: true, Chat: true},
+			started:  true,
+			steps: []step{
+				{from: "p1", msg: react("👍"), err: "not in lobby phase"},
+				{from: "p1", msg: chat("hi"), err: "not in lobby phase"},
+			},
+			want: []lobbyEvent{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestExecution(tt.settings, testQuestions(1, 2)...)
+			conns := map[string]*testConn{"host": {}, "p1": {}}
+			identities := map[string]Identity{"host": {ID: e.Host.ID}, "p1": {ID: "p1", Name: "p1", IsGuest: true}}
+			require.NoError(t, e.HandleMessage(conns["host"], identities["host"], Message{Type: "Join"}))
+			require.NoError(t, e.HandleMessage(conns["p1"], identities["p1"], Message{Type: "Join"}))
+			if tt.started {
+				require.NoError(t, e.HandleMessage(conns["host"], identities["host"], Message{Type: "Start"}))
+			}
+
+			for _, s := range tt.steps {
+				if s.wait {
+					p, ok := e.getParticipant("p1")
+					require.True(t, ok)
+					p.lastReactionAt = p.lastReactionAt.Add(-reactionInterval)
+					p.lastChatAt = p.lastChatAt.Add(-chatInterval)
+				}
+
+				err := e.HandleMessage(conns[s.from], identities[s.from], s.msg)
+				if s.err != "" {
+					require.ErrorContains(t, err, s.err)
+				} else {
+					require.NoError(t, err)
+				}
+			}
+
+			events := []lobbyEvent{}
+			for _, msg := range conns["host"].sent {
+				if event, ok := msg.(lobbyEvent); ok {
+					events = append(events, event)
+				}
+			}
+			require.Equal(t, tt.want, events)
+
+			// Lobby events are only shown on the host screens.
+			for _, msg := range conns["p1"].sent {
+				_, ok := msg.(lobbyEvent)
+				require.False(t, ok)
+			}
+			if tt.muted {
+				require.Equal(t, []string{"p1"}, e.mutedParticipants())
+			} else {
+				require.Empty(t, e.mutedParticipants())
+			}
+		})
+	}
+}
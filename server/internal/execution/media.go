@@ -0,0 +1,133 @@
+package execution
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/william-joh/quizzer/server/internal/quizzer"
+)
+
+// PhaseMedia plays the video of the current question on the host screen. The
+// answer options are locked until the clip has ended.
+const PhaseMedia Phase = "media"
+
+// maxMediaSeconds is how long a clip without a known end may play, when the
+// question has no time limit either.
+const maxMediaSeconds = 5 * 60
+
+// mediaCue tells the host screen which part of a video to play.
+type mediaCue struct {
+	URL      string                `json:"url"`
+	Provider quizzer.VideoProvider `json:"provider"`
+	VideoID  string                `json:"videoId"`
+	// StartSeconds and EndSeconds are where the clip starts and ends in the
+	// video. Without them, the video is played from the start or to the end.
+	StartSeconds *uint64 `json:"startSeconds,omitempty"`
+	EndSeconds   *uint64 `json:"endSeconds,omitempty"`
+}
+
+// getMediaCue returns the video to play for the question, if it has one that
+// can be played.
+func getMediaCue(q quizzer.Question) (mediaCue, bool) {
+	if q.VideoURL == nil {
+		return mediaCue{}, false
+	}
+
+	video, err := quizzer.ParseVideoURL(*q.VideoURL)
+	if err != nil {
+		log.Error().Err(err).Str("questionId", q.ID).Msg("Cannot play video of question")
+		return mediaCue{}, false
+	}
+
+	return mediaCue{
+		URL:          *q.VideoURL,
+		Provider:     video.Provider,
+		VideoID:      video.ID,
+		StartSeconds: q.VideoStartTimeSeconds,
+		EndSeconds:   q.VideoEndTimeSeconds,
+	}, true
+}
+
+// playMedia moves the execution into the media phase if the current question
+// has a video, or opens the question straight away. The question opens on its
+// own once the clip has played, or earlier if the host tells that it ended.
+func (e *Execution) playMedia() {
+	q := e.Questions[e.CurrentQuestion]
+	cue, ok := getMediaCue(q)
+	if !ok {
+		e.openQuestion()
+		return
+	}
+
+	duration, ok := mediaDuration(cue, q)
+	if !ok {
+		log.Error().Str("questionId", q.ID).Msg("Video clip ends before it starts")
+		e.openQuestion()
+		return
+	}
+
+	e.Phase = PhaseMedia
+	e.PhaseDeadline = time.Now().Add(duration)
+}
+
+// mediaDuration returns how long the clip of the cue plays, or false if it
+// ends before it starts. When the end of the clip is not known, it may play
+// for as long as the question is open, so that the game never waits on it
+// forever.
+func mediaDuration(cue mediaCue, q quizzer.Question) (time.Duration, bool) {
+	var start uint64
+	if cue.StartSeconds != nil {
+		start = *cue.StartSeconds
+	}
+
+	seconds := q.TimeLimitSeconds
+	switch {
+	case cue.EndSeconds != nil && *cue.EndSeconds <= start:
+		return 0, false
+	case cue.EndSeconds != nil:
+		seconds = *cue.EndSeconds - start
+	case seconds == 0:
+		seconds = maxMediaSeconds
+	}
+
+	return time.Duration(seconds) * time.Second, true
+}
+
+func (e *Execution) handleMediaEndedMsg(conn Conn) error {
+	if !e.canControl(conn) {
+		log.Error().Msg("Only a host can end the media")
+		return fmt.Errorf("only a host can end the media")
+	}
+
+	if e.Phase != PhaseMedia {
+		log.Error().Msg("Not in media phase")
+		return fmt.Errorf("not in media phase")
+	}
+
+	e.openQuestion()
+
+	// Broadcast the new quiz state
+	if err := e.broadcastQuizState(); err != nil {
+		log.Error().Err(err).Msg("Failed to broadcast quiz state")
+		return fmt.Errorf("broadcast quiz state: %w", err)
+	}
+
+	return nil
+}
+
+// getParticipantMediaPayload returns the state shown to participants while the
+// video of the question plays. The question is shown, but its answer options
+// are not.
+func (e *Execution) getParticipantMediaPayload() (interface{}, error) {
+	q := e.Questions[e.CurrentQuestion]
+	return struct {
+		Phase    string `json:"phase"`
+		Question string `json:"question"`
+		TimeLeft uint64 `json:"timeLeft,omitempty"`
+	}{
+		Phase:    string(e.Phase),
+		Question: q.Question,
+		TimeLeft: e.phaseTimeLeft(),
+	}, nil
+}
@@ -0,0 +1,82 @@
+package execution
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/william-joh/quizzer/server/internal/quizzer"
+)
+
+func TestPlayMedia(t *testing.T) {
+	asPtr := func(v uint64) *uint64 { return &v }
+	asStr := func(v string) *string { return &v }
+	video := "https://youtu.be/dQw4w9WgXcQ"
+
+	tests := []struct {
+		name     string
+		question quizzer.Question
+		phase    Phase
+		duration time.Duration
+	}{
+		{
+			name:     "no video",
+			question: quizzer.Question{TimeLimitSeconds: 20},
+			phase:    PhaseQuestion,
+		},
+		{
+			name:     "unplayable video",
+			question: quizzer.Question{TimeLimitSeconds: 20, VideoURL: asStr("https://example.com/video")},
+			phase:    PhaseQuestion,
+		},
+		{
+			name:     "clip",
+			question: quizzer.Question{TimeLimitSeconds: 20, VideoURL: &video, VideoStartTimeSeconds: asPtr(30), VideoEndTimeSeconds: asPtr(45)},
+			phase:    PhaseMedia,
+			duration: 15 * time.Second,
+		},
+		{
+			name:     "clip from the start",
+			question: quizzer.Question{TimeLimitSeconds: 20, VideoURL: &video, VideoEndTimeSeconds: asPtr(10)},
+			phase:    PhaseMedia,
+			duration: 10 * time.Second,
+		},
+		{
+			name:     "no end plays for the time limit",
+			question: quizzer.Question{TimeLimitSeconds: 20, VideoURL: &video, VideoStartTimeSeconds: asPtr(30)},
+			phase:    PhaseMedia,
+			duration: 20 * time.Second,
+		},
+		{
+			name:     "no end and no time limit",
+			question: quizzer.Question{VideoURL: &video},
+			phase:    PhaseMedia,
+			duration: maxMediaSeconds * time.Second,
+		},
+		{
+			name:     "end before start",
+			question: quizzer.Question{TimeLimitSeconds: 20, VideoURL: &video, VideoStartTimeSeconds: asPtr(45), VideoEndTimeSeconds: asPtr(30)},
+			phase:    PhaseQuestion,
+		},
+		{
+			name:     "empty clip",
+			question: quizzer.Question{TimeLimitSeconds: 20, VideoURL: &video, VideoStartTimeSeconds: asPtr(30), VideoEndTimeSeconds: asPtr(30)},
+			phase:    PhaseQuestion,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestExecution(Settings{TimerEnabled: true}, tt.question)
+
+			before := time.Now()
+			e.playMedia()
+			require.Equal(t, tt.phase, e.Phase)
+
+			if tt.phase == PhaseMedia {
+				require.WithinRange(t, e.PhaseDeadline, before.Add(tt.duration), time.Now().Add(tt.duration))
+			} else {
+				require.True(t, e.PhaseDeadline.IsZero())
+			}
+		})
+	}
+}
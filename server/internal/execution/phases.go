@@ -0,0 +1,225 @@
+package execution
+
+import (
+	"slices"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/william-joh/quizzer/server/internal/quizzer"
+)
+
+// podiumSize is how many participants are on the podium once the quiz has
+// finished.
+const podiumSize = 3
+
+// checkPhaseDeadline moves the execution out of the countdown, media and
+// reveal phases once their time is up, and out of the results, intermission
+// and slide phases when on auto-pilot.
+func (e *Execution) checkPhaseDeadline() {
+	timed := []Phase{PhaseCountdown, PhaseMedia, PhaseReveal, PhaseResults, PhaseIntermission, PhaseSlide}
+	if !slices.Contains(timed, e.Phase) {
+		return
+	}
+
+	if e.PhaseDeadline.IsZero() || time.Now().Before(e.PhaseDeadline) {
+		return
+	}
+
+	switch e.Phase {
+	case PhaseCountdown:
+		e.playMedia()
+	case PhaseMedia:
+		e.openQuestion()
+	case PhaseReveal:
+		e.showResults()
+	case PhaseResults, PhaseSlide, PhaseIntermission:
+		if err := e.next(); err != nil {
+			log.Error().Err(err).Msg("Failed to move on")
+		}
+		return
+	}
+
+	if err := e.broadcastQuizState(); err != nil {
+		log.Error().Err(err).Msg("Failed to broadcast quiz state")
+	}
+}
+
+// openQuestion unlocks the answer options of the current question and starts
+// its timer.
+func (e *Execution) openQuestion() {
+	e.Phase = PhaseQuestion
+	e.PhaseDeadline = time.Time{}
+	e.QuestionStartedAt = time.Now()
+
+	q := e.Questions[e.CurrentQuestion]
+	if limit := e.questionLimit(q); e.Settings.TimerEnabled && limit > 0 {
+		e.QuestionDeadline = time.Now().Add(limit)
+	}
+}
+
+// questionLimit returns how long the question is open, or zero if it is open
+// until everyone answered or the host finishes it. On auto-pilot there is no
+// host to do so, so questions without a time limit get a default one.
+func (e *Execution) questionLimit(q quizzer.Question) time.Duration {
+	seconds := q.TimeLimitSeconds
+	if seconds == 0 && e.Settings.AutoPilot {
+		seconds = defaultQuestionSeconds
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// reveal moves the execution into the reveal phase for the question that just
+// finished, or straight to its results if there is no reveal.
+func (e *Execution) reveal() {
+	if e.Settings.RevealSeconds == 0 {
+		e.showResults()
+		return
+	}
+
+	e.Phase = PhaseReveal
+	e.PhaseDeadline = time.Now().Add(time.Duration(e.Settings.RevealSeconds) * time.Second)
+}
+
+// showResults moves the execution into the results phase, the intermission at
+// the end of a round, or the finished phase once an elimination execution is
+// over. On auto-pilot, the results are only shown for a while.
+func (e *Execution) showResults() {
+	e.Phase = PhaseResults
+	e.PhaseDeadline = time.Time{}
+	if e.Settings.Elimination && e.isOver() {
+		e.Phase = PhaseFinished
+		return
+	}
+
+	if e.endsRound() {
+		e.Phase = PhaseIntermission
+	}
+
+	e.PhaseDeadline = e.autoPilotDeadline()
+}
+
+// autoPilotDeadline returns when to move on from the results, an intermission
+// or a slide on auto-pilot, or zero if the host moves on.
+func (e *Execution) autoPilotDeadline() time.Time {
+	if !e.Settings.AutoPilot {
+		return time.Time{}
+	}
+
+	seconds := e.Settings.ResultsSeconds
+	if seconds == 0 {
+		seconds = defaultResultsSeconds
+	}
+	return time.Now().Add(time.Duration(seconds) * time.Second)
+}
+
+// hasNextQuestion returns whether there is another question to start, either
+// from the quiz or to break a tie in an elimination execution, or a slide to
+// show.
+func (e *Execution) hasNextQuestion() bool {
+	if e.CurrentQuestion < len(e.Questions) || e.hasPendingSlide() {
+		return true
+	}
+	return e.Settings.Elimination && !e.isOver()
+}
+
+// phaseTimeLeft returns the number of whole seconds left in the countdown,
+// media, reveal, results, intermission or slide phase.
+func (e *Execution) phaseTimeLeft() uint64 {
+	left := time.Until(e.PhaseDeadline)
+	if e.PhaseDeadline.IsZero() || left <= 0 {
+		return 0
+	}
+	return uint64(left.Round(time.Second) / time.Second)
+}
+
+// getCountdownPayload returns the state shown to everyone while counting down
+// to a question. The question is shown, but its answer options are not.
+func (e *Execution) getCountdownPayload() (interface{}, error) {
+	q := e.Questions[e.CurrentQuestion]
+	return struct {
+		Phase       string `json:"phase"`
+		Question    string `json:"question"`
+		Countdown   uint64 `json:"countdown"`
+		SuddenDeath bool   `json:"suddenDeath,omitempty"`
+	}{
+		Phase:       string(e.Phase),
+		Question:    q.Question,
+		Countdown:   e.phaseTimeLeft(),
+		SuddenDeath: e.Tiebreaks[q.ID],
+	}, nil
+}
+
+// getHostRevealPayload returns the state shown to the host while revealing the
+// answer to the question that just finished.
+func (e *Execution) getHostRevealPayload() (interface{}, error) {
+	return e.getRevealPayload(true)
+}
+
+// getRevealPayload returns how the question that just finished was answered,
+// with its correct options if they are to be shown.
+func (e *Execution) getRevealPayload(showCorrectOptions bool) (interface{}, error) {
+	q := e.Questions[e.CurrentQuestion-1]
+	payload := struct {
+		Phase          string         `json:"phase"`
+		Question       string         `json:"question"`
+		Options        []option       `json:"options"`
+		CorrectOptions []option       `json:"correctOptions"`
+		AnswerCounts   map[string]int `json:"answerCounts"`
+		IsSkipped      bool           `json:"isSkipped"`
+		TimeLeft       uint64         `json:"timeLeft"`
+	}{
+		Phase:          string(e.Phase),
+		Question:       q.Question,
+		Options:        toOptions(q.Options),
+		CorrectOptions: []option{},
+		AnswerCounts:   map[string]int{},
+		IsSkipped:      e.SkippedQuestions[q.ID],
+		TimeLeft:       e.phaseTimeLeft(),
+	}
+
+	if showCorrectOptions {
+		payload.CorrectOptions = toOptions(q.CorrectOptions())
+	}
+
+	for _, p := range e.Participants {
+		if answer, ok := p.Answers[q.ID]; ok {
+			payload.AnswerCounts[answer]++
+		}
+	}
+
+	return payload, nil
+}
+
+// getParticipantRevealPayload returns the state shown to a participant while
+// revealing the answer to the question that just finished.
+func (e *Execution) getParticipantRevealPayload(p Participant) (interface{}, error) {
+	q := e.Questions[e.CurrentQuestion-1]
+	payload := struct {
+		Phase          string   `json:"phase"`
+		Answer         *string  `json:"answer,omitempty"`
+		IsCorrect      *bool    `json:"isCorrect,omitempty"`
+		CorrectOptions []option `json:"correctOptions,omitempty"`
+		Score          int      `json:"score"`
+		Eliminated     bool     `json:"eliminated,omitempty"`
+		TimeLeft       uint64   `json:"timeLeft"`
+	}{
+		Phase:      string(e.Phase),
+		Score:      p.Scores[q.ID],
+		Eliminated: p.Eliminated,
+		TimeLeft:   e.phaseTimeLeft(),
+	}
+
+	if answer, ok := p.Answers[q.ID]; ok {
+		payload.Answer = &answer
+		if e.Settings.ShowCorrectAnswers {
+			isCorrect := q.IsCorrect(answer)
+			payload.IsCorrect = &isCorrect
+		}
+	}
+
+	if e.Settings.ShowCorrectAnswers {
+		payload.CorrectOptions = toOptions(q.CorrectOptions())
+	}
+
+	return payload, nil
+}
@@ -0,0 +1,226 @@
+package execution
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/william-joh/quizzer/server/internal/quizzer"
+)
+
+func TestOpenQuestion(t *testing.T) {
+	options := []quizzer.AnswerOption{{ID: "o1", IsCorrect: true}, {ID: "o2"}}
+	timed := quizzer.Question{ID: "q1", TimeLimitSeconds: 20, Options: options}
+	untimed := quizzer.Question{ID: "q1", Options: options}
+
+	tests := []struct {
+		name     string
+		settings Settings
+		question quizzer.Question
+		limit    time.Duration
+	}{
+		{
+			name:     "time limit",
+			settings: Settings{TimerEnabled: true},
+			question: timed,
+			limit:    20 * time.Second,
+		},
+		{
+			name:     "no time limit",
+			settings: Settings{TimerEnabled: true},
+			question: untimed,
+		},
+		{
+			name:     "timer disabled",
+			settings: Settings{},
+			question: timed,
+		},
+		{
+			name:     "time limit on auto-pilot",
+			settings: Settings{TimerEnabled: true, AutoPilot: true},
+			question: timed,
+			limit:    20 * time.Second,
+		},
+		{
+			name:     "no time limit on auto-pilot",
+			settings: Settings{TimerEnabled: true, AutoPilot: true},
+			question: untimed,
+			limit:    defaultQuestionSeconds * time.Second,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestExecution(tt.settings, tt.question)
+			e.openQuestion()
+
+			require.Equal(t, PhaseQuestion, e.Phase)
+			if tt.limit == 0 {
+				require.True(t, e.QuestionDeadline.IsZero())
+				return
+			}
+			require.WithinDuration(t, time.Now().Add(tt.limit), e.QuestionDeadline, time.Second)
+		})
+	}
+
+	t.Run("auto-pilot does not wait for a participant who never answers", func(t *testing.T) {
+		e := newTestExecution(Settings{TimerEnabled: true, AutoPilot: true}, untimed)
+		e.Participants = []Participant{
+			{ID: "p1", Conn: &testConn{}, Answers: map[string]string{"q1": "o1"}, Scores: map[string]int{}, answerTimeLeft: map[string]time.Duration{}, answeredAt: map[string]time.Time{}},
+			{ID: "p2", Conn: &testConn{}, Answers: map[string]string{}, Scores: map[string]int{}, answerTimeLeft: map[string]time.Duration{}, answeredAt: map[string]time.Time{}},
+		}
+		e.openQuestion()
+
+		e.checkQuestionFinished()
+		require.Equal(t, PhaseQuestion, e.Phase)
+
+		e.QuestionDeadline = time.Now().Add(-time.Second)
+		e.checkQuestionFinished()
+		require.Equal(t, PhaseResults, e.Phase)
+		require.Equal(t, 1, e.Participants[0].Scores["q1"])
+	})
+}
+
+func TestCheckPhaseDeadline(t *testing.T) {
+	video := "https://youtu.be/dQw4w9WgXcQ"
+	end := uint64(10)
+	options := []quizzer.AnswerOption{{ID: "o1", IsCorrect: true}, {ID: "o2"}}
+	questions := []quizzer.Question{
+		{ID: "q1", TimeLimitSeconds: 20, Options: options},
+		{ID: "q2", TimeLimitSeconds: 20, Options: options},
+	}
+	withVideo := []quizzer.Question{{ID: "q1", TimeLimitSeconds: 20, VideoURL: &video, VideoEndTimeSeconds: &end, Options: options}}
+
+	tests := []struct {
+		name      string
+		settings  Settings
+		questions []quizzer.Question
+		phase     Phase
+		// current is the index of the current question.
+		current int
+		// deadline is when the phase ends, relative to now, unless it has
+		// no deadline.
+		deadline   time.Duration
+		noDeadline bool
+		want       Phase
+		// wantCurrent is the index of the current question after the
+		// deadline was checked.
+		wantCurrent int
+	}{
+		{
+			name:      "countdown ends",
+			settings:  Settings{TimerEnabled: true, CountdownSeconds: 3},
+			questions: questions,
+			phase:     PhaseCountdown,
+			deadline:  -time.Second,
+			want:      PhaseQuestion,
+		},
+		{
+			name:      "countdown is still running",
+			settings:  Settings{TimerEnabled: true, CountdownSeconds: 3},
+			questions: questions,
+			phase:     PhaseCountdown,
+			deadline:  time.Second,
+			want:      PhaseCountdown,
+		},
+		{
+			name:      "countdown ends into the video",
+			settings:  Settings{TimerEnabled: true, CountdownSeconds: 3},
+			questions: withVideo,
+			phase:     PhaseCountdown,
+			deadline:  -time.Second,
+			want:      PhaseMedia,
+		},
+		{
+			name:      "video ends",
+			settings:  Settings{TimerEnabled: true},
+			questions: withVideo,
+			phase:     PhaseMedia,
+			deadline:  -time.Second,
+			want:      PhaseQuestion,
+		},
+		{
+			name:        "reveal ends",
+			settings:    Settings{RevealSeconds: 5},
+			questions:   questions,
+			phase:       PhaseReveal,
+			current:     1,
+			deadline:    -time.Second,
+			want:        PhaseResults,
+			wantCurrent: 1,
+		},
+		{
+			name:        "reveal is still running",
+			settings:    Settings{RevealSeconds: 5},
+			questions:   questions,
+			phase:       PhaseReveal,
+			current:     1,
+			deadline:    time.Second,
+			want:        PhaseReveal,
+			wantCurrent: 1,
+		},
+		{
+			name:        "results end on auto-pilot",
+			settings:    Settings{TimerEnabled: true, AutoPilot: true, CountdownSeconds: 3},
+			questions:   questions,
+			phase:       PhaseResults,
+			current:     1,
+			deadline:    -time.Second,
+			want:        PhaseCountdown,
+			wantCurrent: 1,
+		},
+		{
+			name:        "results of the last question end on auto-pilot",
+			settings:    Settings{TimerEnabled: true, AutoPilot: true},
+			questions:   questions,
+			phase:       PhaseResults,
+			current:     2,
+			deadline:    -time.Second,
+			want:        PhaseFinished,
+			wantCurrent: 2,
+		},
+		{
+			name:        "results wait for the host",
+			settings:    Settings{TimerEnabled: true},
+			questions:   questions,
+			phase:       PhaseResults,
+			current:     1,
+			noDeadline:  true,
+			want:        PhaseResults,
+			wantCurrent: 1,
+		},
+		{
+			name:      "question is not timed by the phase deadline",
+			settings:  Settings{TimerEnabled: true},
+			questions: questions,
+			phase:     PhaseQuestion,
+			deadline:  -time.Second,
+			want:      PhaseQuestion,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestExecution(tt.settings, tt.questions...)
+			host := &testConn{}
+			e.HostConn = host
+			e.Phase = tt.phase
+			e.CurrentQuestion = tt.current
+			if !tt.noDeadline {
+				e.PhaseDeadline = time.Now().Add(tt.deadline)
+			}
+
+			e.checkPhaseDeadline()
+			require.Equal(t, tt.want, e.Phase)
+			require.Equal(t, tt.wantCurrent, e.CurrentQuestion)
+
+			// The host is told when the phase changes, and only then.
+			if tt.want == tt.phase {
+				require.Empty(t, host.sent)
+				return
+			}
+			require.NotEmpty(t, host.sent)
+			if tt.want == PhaseQuestion {
+				require.WithinDuration(t, time.Now().Add(20*time.Second), e.QuestionDeadline, time.Second)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package execution
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog/log"
+	"github.com/william-joh/quizzer/server/internal/postgres"
+	"github.com/william-joh/quizzer/server/internal/quizzer"
+)
+
+const (
+	// schedulerInterval is how often the scheduler looks for scheduled games
+	// to open.
+	schedulerInterval = 5 * time.Second
+	// maxScheduleDelay is how late a scheduled game may still be opened, e.g.
+	// when the server was down at its start time.
+	maxScheduleDelay = 15 * time.Minute
+)
+
+// openScheduledGames opens the lobbies of the scheduled games that are due.
+func (s *inMemoryService) openScheduledGames(ctx context.Context, now time.Time) error {
+	games, err := s.db.Do(ctx).ListDueScheduledGames(ctx, now)
+	if err != nil {
+		return fmt.Errorf("list due scheduled games: %w", err)
+	}
+
+	for _, game := range games {
+		var code string
+		if err := s.db.InTx(ctx, func(tx postgres.Session) error {
+			var err error
+			code, err = s.openDueScheduledGame(ctx, tx, game.ID, now)
+			return err
+		}); err != nil {
+			log.Error().Err(err).Str("id", game.ID).Msg("Failed to update scheduled game")
+
+			// The game is still pending and is opened again, so the lobby
+			// that was opened for it must not be left behind.
+			if code != "" {
+				s.removeExecution(code)
+			}
+		}
+	}
+
+	return nil
+}
+
+// openDueScheduledGame opens the lobby of the scheduled game, or marks it as
+// missed if it is too late. The game is locked while it is opened, and left
+// alone if it was cancelled or changed since it was listed. The code of the
+// opened lobby is returned, also when the game could not be updated.
+func (s *inMemoryService) openDueScheduledGame(ctx context.Context, tx postgres.Session, id string, now time.Time) (string, error) {
+	game, err := tx.LockScheduledGame(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		log.Debug().Str("id", id).Msg("Scheduled game was cancelled")
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("lock scheduled game: %w", err)
+	}
+	if game.Status != quizzer.ScheduledGameStatusPending || game.StartAt.After(now) {
+		return "", nil
+	}
+
+	if now.Sub(game.StartAt) > maxScheduleDelay {
+		log.Warn().Str("id", game.ID).Time("startAt", game.StartAt).Msg("Scheduled game was not opened in time")
+		game.Status = quizzer.ScheduledGameStatusMissed
+	} else {
+		code, err := s.openScheduledGame(ctx, game)
+		if err != nil {
+			// The game is tried again until it is too late.
+			log.Error().Err(err).Str("id", game.ID).Msg("Failed to open scheduled game")
+			return "", nil
+		}
+
+		log.Info().Str("id", game.ID).Str("code", code).Msg("Opened scheduled game")
+		game.Status = quizzer.ScheduledGameStatusOpened
+		game.Code = &code
+	}
+
+	code := ""
+	if game.Code != nil {
+		code = *game.Code
+	}
+	if err := tx.UpdateScheduledGame(ctx, game); err != nil {
+		return code, fmt.Errorf("update scheduled game: %w", err)
+	}
+	return code, nil
+}
+
+func (s *inMemoryService) openScheduledGame(ctx context.Context, game quizzer.ScheduledGame) (string, error) {
+	settings := DefaultSettings()
+	if len(game.Settings) > 0 {
+		if err := json.Unmarshal(game.Settings, &settings); err != nil {
+			return "", fmt.Errorf("decode settings: %w", err)
+		}
+	}
+
+	return s.CreateExecution(ctx, game.QuizID, game.HostID, settings)
+}
@@ -0,0 +1,145 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/william-joh/quizzer/server/internal/postgres"
+	"github.com/william-joh/quizzer/server/internal/quizzer"
+)
+
+// schedulerDB is a database with a scheduled game of a quiz without questions.
+// Transactions fail when the session fails.
+type schedulerDB struct {
+	postgres.Session
+	game      quizzer.ScheduledGame
+	quizErr   error
+	updateErr error
+	// onUpdate is called with the game when it is updated.
+	onUpdate func(quizzer.ScheduledGame)
+}
+
+func (db *schedulerDB) Do(ctx context.Context) postgres.Session { return db }
+
+func (db *schedulerDB) InTx(ctx context.Context, f func(postgres.Session) error) error {
+	return f(db)
+}
+
+func (db *schedulerDB) Close() error { return nil }
+
+func (db *schedulerDB) ListDueScheduledGames(ctx context.Context, at time.Time) ([]quizzer.ScheduledGame, error) {
+	return []quizzer.ScheduledGame{db.game}, nil
+}
+
+func (db *schedulerDB) LockScheduledGame(ctx context.Context, id string) (quizzer.ScheduledGame, error) {
+	return db.game, nil
+}
+
+func (db *schedulerDB) UpdateScheduledGame(ctx context.Context, game quizzer.ScheduledGame) error {
+	db.onUpdate(game)
+	return db.updateErr
+}
+
+func (db *schedulerDB) GetQuiz(ctx context.Context, id string) (quizzer.Quiz, error) {
+	return quizzer.Quiz{ID: id, CreatedBy: db.game.HostID}, db.quizErr
+}
+
+func (db *schedulerDB) ListQuestions(ctx context.Context, quizID string) ([]quizzer.Question, error) {
+	return []quizzer.Question{}, nil
+}
+
+func (db *schedulerDB) ListSlides(ctx context.Context, quizID string) ([]quizzer.Slide, error) {
+	return []quizzer.Slide{}, nil
+}
+
+func (db *schedulerDB) ListSections(ctx context.Context, quizID string) ([]quizzer.Section, error) {
+	return []quizzer.Section{}, nil
+}
+
+func (db *schedulerDB) GetUser(ctx context.Context, id string) (quizzer.User, error) {
+	return quizzer.User{ID: id}, nil
+}
+
+func TestOpenScheduledGames(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name      string
+		startAt   time.Time
+		quizErr   error
+		updateErr error
+		// status is what the game is updated to, if it is updated.
+		status quizzer.ScheduledGameStatus
+		opened bool
+	}{
+		{
+			name:    "due",
+			startAt: now.Add(-time.Minute),
+			status:  quizzer.ScheduledGameStatusOpened,
+			opened:  true,
+		},
+		{
+			name:    "too late",
+			startAt: now.Add(-time.Hour),
+			status:  quizzer.ScheduledGameStatusMissed,
+		},
+		{
+			name:    "quiz cannot be loaded",
+			startAt: now.Add(-time.Minute),
+			quizErr: errors.New("db down"),
+		},
+		{
+			name:      "game cannot be updated",
+			startAt:   now.Add(-time.Minute),
+			updateErr: errors.New("db down"),
+			status:    quizzer.ScheduledGameStatusOpened,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &schedulerDB{
+				game:      quizzer.ScheduledGame{ID: "game-id", QuizID: "quiz-id", HostID: "host-id", StartAt: tt.startAt, Status: quizzer.ScheduledGameStatusPending},
+				quizErr:   tt.quizErr,
+				updateErr: tt.updateErr,
+			}
+			s := NewInMemory(db, DefaultCodeConfig()).(*inMemoryService)
+
+			// The lobby that was opened for the game is kept hold of when
+			// the game is updated, to check that it is ended if the update
+			// fails.
+			var updated *quizzer.ScheduledGame
+			var opened *Execution
+			db.onUpdate = func(game quizzer.ScheduledGame) {
+				updated = &game
+				if game.Code != nil {
+					opened = s.executions[*game.Code]
+				}
+			}
+
+			require.NoError(t, s.openScheduledGames(context.Background(), now))
+
+			if tt.status == "" {
+				require.Nil(t, updated)
+			} else {
+				require.NotNil(t, updated)
+				require.Equal(t, tt.status, updated.Status)
+			}
+
+			if !tt.opened {
+				// A game that is not marked as opened is opened again, so
+				// no lobby may be left for it.
+				require.Empty(t, s.executions)
+				if tt.updateErr != nil {
+					require.True(t, opened.IsDone)
+				}
+				return
+			}
+			require.Len(t, s.executions, 1)
+			require.NotNil(t, updated.Code)
+			require.Contains(t, s.executions, *updated.Code)
+		})
+	}
+}
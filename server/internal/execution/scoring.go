@@ -0,0 +1,131 @@
+package execution
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/william-joh/quizzer/server/internal/quizzer"
+)
+
+const (
+	// maxSpeedPoints is the number of points given for an instant correct
+	// answer in speed scoring mode.
+	maxSpeedPoints = 1000
+	// minSpeedPoints is the number of points given for a correct answer in the
+	// last moment in speed scoring mode.
+	minSpeedPoints = 500
+)
+
+type participantResult struct {
+	Name      string `json:"name"`
+	NrCorrect int    `json:"nrCorrect"`
+	Score     int    `json:"score"`
+	Avatar    string `json:"avatar,omitempty"`
+	Color     string `json:"color,omitempty"`
+	Team      string `json:"team,omitempty"`
+	// Eliminated is set for participants that are out of an elimination
+	// execution.
+	Eliminated bool `json:"eliminated,omitempty"`
+	// lasted is the number of questions the participant survived.
+	lasted int
+	// userID identifies the participant, also on an anonymous leaderboard.
+	userID string
+	// scores are the points of the participant per question.
+	scores map[string]int
+}
+
+// score returns the points the participant gets for their answer to the
+// question, multiplied for its section. When teams huddle, the answer of their
+// team counts instead.
+func (e *Execution) score(q quizzer.Question, p Participant) int {
+	answer, ok := p.Answers[q.ID]
+	timeLeft := p.answerTimeLeft[q.ID]
+	if e.Settings.TeamHuddle && p.Team != "" {
+		answer, timeLeft, ok = e.huddleAnswer(q, p.Team)
+	}
+
+	if !ok {
+		return 0
+	}
+	return Score(e.Settings, q, answer, timeLeft) * e.multiplier(q)
+}
+
+// Score returns the points for answering the question with the answer option,
+// when timeLeft was left on the question timer.
+func Score(settings Settings, q quizzer.Question, optionID string, timeLeft time.Duration) int {
+	if !q.IsCorrect(optionID) {
+		return 0
+	}
+
+	switch settings.ScoringMode {
+	case ScoringModeSpeed:
+		limit := time.Duration(q.TimeLimitSeconds) * time.Second
+		if !settings.TimerEnabled || limit <= 0 {
+			return maxSpeedPoints
+		}
+
+		left := min(timeLeft, limit)
+		return minSpeedPoints + int(int64(maxSpeedPoints-minSpeedPoints)*int64(left)/int64(limit))
+	default:
+		return 1
+	}
+}
+
+// totalScore returns the points of the participant on the finished questions
+// that were not skipped, and on how many of them they scored.
+func (e *Execution) totalScore(p Participant) (int, int) {
+	var total, nrCorrect int
+	for _, q := range e.Questions[:e.CurrentQuestion] {
+		if e.SkippedQuestions[q.ID] {
+			continue
+		}
+
+		score := p.Scores[q.ID]
+		if score > 0 {
+			nrCorrect++
+		}
+		total += score
+	}
+	return total, nrCorrect
+}
+
+// getResults returns the leaderboard of the finished questions, ordered by
+// score. Participants that left during the quiz keep their place on it. In an
+// elimination execution, participants that lasted longer rank higher
+// regardless of their score. The team and round standings are made from it.
+func (e *Execution) getResults() []participantResult {
+	results := []participantResult{}
+	for i, p := range e.allParticipants() {
+		result := participantResult{Name: p.Name, Team: p.Team, Eliminated: p.Eliminated, lasted: p.EliminatedIn, userID: p.ID, scores: p.Scores}
+		if !p.Eliminated {
+			result.lasted = len(e.Questions) + 1
+		}
+		if e.Settings.AnonymousLeaderboard {
+			result.Name = fmt.Sprintf("Player %d", i+1)
+		} else {
+			result.Avatar = p.Avatar
+			result.Color = p.Color
+		}
+		result.Score, result.NrCorrect = e.totalScore(p)
+
+		results = append(results, result)
+	}
+
+	slices.SortStableFunc(results, func(a, b participantResult) int {
+		if e.Settings.Elimination && a.lasted != b.lasted {
+			return cmp.Compare(b.lasted, a.lasted)
+		}
+		return cmp.Compare(b.Score, a.Score)
+	})
+
+	return results
+}
+
+// rank returns the place of the participant on the leaderboard, starting at 1.
+func (e *Execution) rank(p Participant) int {
+	return slices.IndexFunc(e.getResults(), func(r participantResult) bool {
+		return r.userID == p.ID
+	}) + 1
+}
@@ -0,0 +1,181 @@
+package execution
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/william-joh/quizzer/server/internal/quizzer"
+)
+
+func TestScore(t *testing.T) {
+	q := quizzer.Question{ID: "q1", TimeLimitSeconds: 20, Options: []quizzer.AnswerOption{
+		{ID: "o1", IsCorrect: true},
+		{ID: "o2"},
+	}}
+	speed := Settings{ScoringMode: ScoringModeSpeed, TimerEnabled: true}
+
+	tests := []struct {
+		name     string
+		settings Settings
+		question quizzer.Question
+		optionID string
+		timeLeft time.Duration
+		want     int
+	}{
+		{
+			name:     "correct answer",
+			settings: Settings{ScoringMode: ScoringModeCorrect},
+			question: q,
+			optionID: "o1",
+			timeLeft: 5 * time.Second,
+			want:     1,
+		},
+		{
+			name:     "wrong answer",
+			settings: Settings{ScoringMode: ScoringModeCorrect},
+			question: q,
+			optionID: "o2",
+			want:     0,
+		},
+		{
+			name:     "unknown option",
+			settings: speed,
+			question: q,
+			optionID: "o3",
+			timeLeft: 20 * time.Second,
+			want:     0,
+		},
+		{
+			name:     "instant speed answer",
+			settings: speed,
+			question: q,
+			optionID: "o1",
+			timeLeft: 20 * time.Second,
+			want:     maxSpeedPoints,
+		},
+		{
+			name:     "speed answer halfway",
+			settings: speed,
+			question: q,
+			optionID: "o1",
+			timeLeft: 10 * time.Second,
+			want:     750,
+		},
+		{
+			name:     "speed answer in the last moment",
+			settings: speed,
+			question: q,
+			optionID: "o1",
+			want:     minSpeedPoints,
+		},
+		{
+			name:     "more time left than the limit",
+			settings: speed,
+			question: q,
+			optionID: "o1",
+			timeLeft: time.Minute,
+			want:     maxSpeedPoints,
+		},
+		{
+			name:     "speed answer without timer",
+			settings: Settings{ScoringMode: ScoringModeSpeed},
+			question: q,
+			optionID: "o1",
+			want:     maxSpeedPoints,
+		},
+		{
+			name:     "speed answer without time limit",
+			settings: speed,
+			question: quizzer.Question{ID: "q2", Options: q.Options},
+			optionID: "o1",
+			want:     maxSpeedPoints,
+		},
+		{
+			name:     "wrong speed answer",
+			settings: speed,
+			question: q,
+			optionID: "o2",
+			timeLeft: 20 * time.Second,
+			want:     0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, Score(tt.settings, tt.question, tt.optionID, tt.timeLeft))
+		})
+	}
+}
+
+func TestResultsKeepDepartedParticipants(t *testing.T) {
+	section := quizzer.Section{ID: "s1", Title: "round 1"}
+	question := quizzer.Question{ID: "q1", Question: "q1", SectionID: &section.ID, Options: []quizzer.AnswerOption{
+		{ID: "o1", Text: "a", IsCorrect: true},
+		{ID: "o2", Text: "b"},
+	}}
+	p1 := Identity{ID: "p1", Name: "p1", IsGuest: true}
+	p2 := Identity{ID: "p2", Name: "p2", IsGuest: true}
+	p3 := Identity{ID: "p3", Name: "p3", IsGuest: true}
+
+	// Every participant answers correctly, then the first, who is in the red
+	// team with the third, leaves before the results.
+	e := newTestExecution(Settings{Teams: []string{"red", "blue"}}, question)
+	e.Sections = []quizzer.Section{section}
+	host, c1, c2, c3 := &testConn{}, &testConn{}, &testConn{}, &testConn{}
+	require.NoError(t, e.HandleMessage(host, Identity{ID: e.Host.ID}, Message{Type: "Join"}))
+	require.NoError(t, e.HandleMessage(c1, p1, Message{Type: "Join"}))
+	require.NoError(t, e.HandleMessage(c2, p2, Message{Type: "Join"}))
+	require.NoError(t, e.HandleMessage(c3, p3, Message{Type: "Join"}))
+	require.NoError(t, e.HandleMessage(host, Identity{ID: e.Host.ID}, Message{Type: "Start"}))
+	for _, p := range []struct {
+		conn     *testConn
+		identity Identity
+	}{{c1, p1}, {c2, p2}, {c3, p3}} {
+		require.NoError(t, e.HandleMessage(p.conn, p.identity, Message{Type: "AnswerQuestion", Data: map[string]any{"optionId": "o1"}}))
+	}
+	require.NoError(t, e.Disconnect(c1))
+	require.NoError(t, e.HandleMessage(host, Identity{ID: e.Host.ID}, Message{Type: "FinishQuestion"}))
+	require.Len(t, e.Participants, 2)
+
+	scores := func(results []participantResult) map[string]int {
+		s := map[string]int{}
+		for _, r := range results {
+			s[r.Name] = r.Score
+		}
+		return s
+	}
+	all := map[string]int{"p1": 1, "p2": 1, "p3": 1}
+
+	tests := []struct {
+		name   string
+		scores func() map[string]int
+		want   map[string]int
+	}{
+		{
+			name:   "leaderboard",
+			scores: func() map[string]int { return scores(e.getResults()) },
+			want:   all,
+		},
+		{
+			name:   "round standings",
+			scores: func() map[string]int { return scores(e.roundResults(section)) },
+			want:   all,
+		},
+		{
+			name: "team standings",
+			scores: func() map[string]int {
+				s := map[string]int{}
+				for _, r := range e.getTeamResults() {
+					s[r.Name] = int(r.Score)
+				}
+				return s
+			},
+			want: map[string]int{"red": 2, "blue": 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.scores())
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package execution
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/william-joh/quizzer/server/internal/quizzer"
+)
+
+// PhaseIntermission shows the standings of the round that just ended, and of
+// the quiz so far, before the next round starts or the quiz finishes. It
+// replaces the results phase at the end of every round, including the last.
+const PhaseIntermission Phase = "intermission"
+
+// section returns the section the question belongs to.
+func (e *Execution) section(q quizzer.Question) (quizzer.Section, bool) {
+	if q.SectionID == nil {
+		return quizzer.Section{}, false
+	}
+
+	i := slices.IndexFunc(e.Sections, func(s quizzer.Section) bool { return s.ID == *q.SectionID })
+	if i < 0 {
+		return quizzer.Section{}, false
+	}
+	return e.Sections[i], true
+}
+
+// multiplier returns what the points for the question are multiplied by.
+func (e *Execution) multiplier(q quizzer.Question) int {
+	s, ok := e.section(q)
+	if !ok {
+		return 1
+	}
+	return s.Factor()
+}
+
+// endsRound returns whether the question that just finished is the last one of
+// its section, either because another section follows or because it was the
+// last question.
+func (e *Execution) endsRound() bool {
+	if e.CurrentQuestion == 0 || e.CurrentQuestion > len(e.Questions) {
+		return false
+	}
+
+	finished := e.Questions[e.CurrentQuestion-1]
+	if finished.SectionID == nil {
+		return false
+	}
+	return e.CurrentQuestion == len(e.Questions) || !sameSection(finished, e.Questions[e.CurrentQuestion])
+}
+
+// roundResults returns the leaderboard of the finished questions of the
+// section, ordered by score.
+func (e *Execution) roundResults(section quizzer.Section) []participantResult {
+	results := e.getResults()
+	for i := range results {
+		results[i].Score, results[i].NrCorrect = 0, 0
+		for _, q := range e.Questions[:e.CurrentQuestion] {
+			if e.SkippedQuestions[q.ID] || q.SectionID == nil || *q.SectionID != section.ID {
+				continue
+			}
+
+			if score := results[i].scores[q.ID]; score > 0 {
+				results[i].Score += score
+				results[i].NrCorrect++
+			}
+		}
+	}
+
+	slices.SortStableFunc(results, func(a, b participantResult) int {
+		return cmp.Compare(b.Score, a.Score)
+	})
+	return results
+}
+
+// roundSection returns the section that just ended and its number, starting at
+// 1.
+func (e *Execution) roundSection() (quizzer.Section, int) {
+	s, _ := e.section(e.Questions[e.CurrentQuestion-1])
+	return s, slices.IndexFunc(e.Sections, func(other quizzer.Section) bool { return other.ID == s.ID }) + 1
+}
+
+// getHostIntermissionPayload returns the standings shown to the host between
+// two rounds.
+func (e *Execution) getHostIntermissionPayload() (interface{}, error) {
+	section, round := e.roundSection()
+	return struct {
+		Phase        string              `json:"phase"`
+		Section      string              `json:"section"`
+		Round        int                 `json:"round"`
+		TotalRounds  int                 `json:"totalRounds"`
+		RoundResults []participantResult `json:"roundResults"`
+		Results      []participantResult `json:"results"`
+		TeamResults  []teamResult        `json:"teamResults,omitempty"`
+		TimeLeft     uint64              `json:"timeLeft,omitempty"`
+	}{
+		Phase:        string(e.Phase),
+		Section:      section.Title,
+		Round:        round,
+		TotalRounds:  len(e.Sections),
+		RoundResults: e.roundResults(section),
+		Results:      e.getResults(),
+		TeamResults:  e.getTeamResults(),
+		TimeLeft:     e.phaseTimeLeft(),
+	}, nil
+}
+
+// getParticipantIntermissionPayload returns how the participant did in the
+// round that just ended, and in the quiz so far.
+func (e *Execution) getParticipantIntermissionPayload(p Participant) (interface{}, error) {
+	section, round := e.roundSection()
+	payload := struct {
+		Phase      string `json:"phase"`
+		Section    string `json:"section"`
+		Round      int    `json:"round"`
+		RoundScore int    `json:"roundScore"`
+		RoundRank  int    `json:"roundRank"`
+		TotalScore int    `json:"totalScore"`
+		Rank       int    `json:"rank"`
+	}{
+		Phase:   string(e.Phase),
+		Section: section.Title,
+		Round:   round,
+		Rank:    e.rank(p),
+	}
+
+	payload.TotalScore, _ = e.totalScore(p)
+	for i, r := range e.roundResults(section) {
+		if r.userID == p.ID {
+			payload.RoundScore = r.Score
+			payload.RoundRank = i + 1
+		}
+	}
+
+	return payload, nil
+}
@@ -0,0 +1,116 @@
+package execution
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/william-joh/quizzer/server/internal/quizzer"
+)
+
+func TestEndsRound(t *testing.T) {
+	a, b := "a", "b"
+	// The quiz is: question 0 and 1 in section a, question 2 in none,
+	// question 3 and 4 in section b, and question 5 in section a again.
+	questions := []quizzer.Question{
+		{ID: "q0", SectionID: &a},
+		{ID: "q1", SectionID: &a},
+		{ID: "q2"},
+		{ID: "q3", SectionID: &b},
+		{ID: "q4", SectionID: &b},
+		{ID: "q5", SectionID: &a},
+	}
+
+	tests := []struct {
+		name            string
+		questions       []quizzer.Question
+		currentQuestion int
+		want            bool
+	}{
+		{
+			name:            "before the first question",
+			currentQuestion: 0,
+			want:            false,
+		},
+		{
+			name:            "within a section",
+			currentQuestion: 1,
+			want:            false,
+		},
+		{
+			name:            "section followed by a question without one",
+			currentQuestion: 2,
+			want:            true,
+		},
+		{
+			name:            "question without a section",
+			currentQuestion: 3,
+			want:            false,
+		},
+		{
+			name:            "section followed by another",
+			currentQuestion: 5,
+			want:            true,
+		},
+		{
+			name:            "last round",
+			currentQuestion: 6,
+			want:            true,
+		},
+		{
+			name:            "last question without a section",
+			questions:       questions[:3],
+			currentQuestion: 3,
+			want:            false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			qs := questions
+			if tt.questions != nil {
+				qs = tt.questions
+			}
+			e := newTestExecution(Settings{}, qs...)
+			e.CurrentQuestion = tt.currentQuestion
+
+			require.Equal(t, tt.want, e.endsRound())
+		})
+	}
+}
+
+func TestIntermission(t *testing.T) {
+	options := []quizzer.AnswerOption{{ID: "o1", IsCorrect: true}, {ID: "o2"}}
+	sections := []quizzer.Section{{ID: "a", Title: "round a"}, {ID: "b", Title: "round b"}}
+	e := newTestExecution(Settings{},
+		quizzer.Question{ID: "q1", SectionID: &sections[0].ID, Options: options},
+		quizzer.Question{ID: "q2", SectionID: &sections[1].ID, Options: options},
+	)
+	e.Sections = sections
+	host := &testConn{}
+	require.NoError(t, e.HandleMessage(host, Identity{ID: e.Host.ID}, Message{Type: "Join"}))
+	require.NoError(t, e.HandleMessage(&testConn{}, Identity{ID: "p1", Name: "p1", IsGuest: true}, Message{Type: "Join"}))
+	require.NoError(t, e.HandleMessage(host, Identity{ID: e.Host.ID}, Message{Type: "Start"}))
+
+	// Every round ends with its intermission, the last one before the quiz
+	// finishes.
+	for _, want := range []struct {
+		phase Phase
+		round string
+	}{
+		{phase: PhaseIntermission, round: "round a"},
+		{phase: PhaseQuestion},
+		{phase: PhaseIntermission, round: "round b"},
+		{phase: PhaseFinished},
+	} {
+		msg := "NextQuestion"
+		if e.Phase == PhaseQuestion {
+			msg = "FinishQuestion"
+		}
+		require.NoError(t, e.HandleMessage(host, Identity{ID: e.Host.ID}, Message{Type: msg}))
+		require.Equal(t, want.phase, e.Phase)
+
+		if want.phase == PhaseIntermission {
+			section, _ := e.roundSection()
+			require.Equal(t, want.round, section.Title)
+		}
+	}
+}
@@ -3,8 +3,10 @@ package execution
 import (
 	"context"
 	"errors"
+	"fmt"
 	"math/rand"
-	"strconv"
+	"slices"
+	"sync"
 	"time"
 
 	"github.com/rs/zerolog/log"
@@ -12,16 +14,20 @@ import (
 )
 
 type Service interface {
-	CreateExecution(ctx context.Context, quizId string, hostId string) (string, error)
+	CreateExecution(ctx context.Context, quizId string, hostId string, settings Settings) (string, error)
 	GetExecution(ctx context.Context, code string) (*Execution, error)
+	// ListExecutions returns the running executions the user is the host or
+	// a co-host of, oldest first.
+	ListExecutions(ctx context.Context, userID string) ([]*Execution, error)
 
 	Run()
 	Stop()
 }
 
-func NewInMemory(db postgres.Database) Service {
+func NewInMemory(db postgres.Database, codes CodeConfig) Service {
 	return &inMemoryService{
 		db:         db,
+		codes:      codes,
 		executions: map[string]*Execution{},
 		done:       make(chan bool),
 	}
@@ -29,15 +35,20 @@ func NewInMemory(db postgres.Database) Service {
 
 type inMemoryService struct {
 	db         postgres.Database
+	codes      CodeConfig
 	executions map[string]*Execution
 	done       chan bool
+	mu         sync.Mutex
 }
 
 // Run is a method that should periodically check if there are any executions that are done and if so, clean them up.
+// It also opens the lobbies of scheduled games when they are due.
 func (s *inMemoryService) Run() {
 	go func() {
 		ticker := time.NewTicker(time.Second * 10)
 		defer ticker.Stop()
+		scheduler := time.NewTicker(schedulerInterval)
+		defer scheduler.Stop()
 
 		for {
 			select {
@@ -46,45 +57,83 @@ func (s *inMemoryService) Run() {
 				return
 			case <-ticker.C:
 				log.Trace().Msg("Checking for done executions")
-				for code, execution := range s.executions {
-					if execution.IsDone {
-						log.Debug().Str("code", code).Msg("Execution is done, cleaning up")
-						execution.Close()
-						delete(s.executions, code)
-					}
-
-					// Check if the execution was created more than 1 hour ago and if so, clean it up
-					if time.Since(execution.CreatedAt) > time.Hour {
-						log.Debug().Str("code", code).Msg("Execution is older than 1 hour, cleaning up")
-						execution.Close()
-						delete(s.executions, code)
+				for _, execution := range s.removeFinished() {
+					if err := s.saveGame(context.Background(), execution); err != nil {
+						log.Error().Err(err).Str("code", execution.Code).Msg("Failed to save game")
 					}
+					execution.Close()
+				}
+			case <-scheduler.C:
+				if err := s.openScheduledGames(context.Background(), time.Now()); err != nil {
+					log.Error().Err(err).Msg("Failed to open scheduled games")
 				}
 			}
 		}
 	}()
 }
 
+// removeFinished removes the executions that are done or too old from the
+// service and returns them.
+func (s *inMemoryService) removeFinished() []*Execution {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	finished := []*Execution{}
+	for code, execution := range s.executions {
+		done, createdAt := execution.lifetime()
+		if done {
+			log.Debug().Str("code", code).Msg("Execution is done, cleaning up")
+		} else if time.Since(createdAt) > time.Hour {
+			// Check if the execution was created more than 1 hour ago and if so, clean it up
+			log.Debug().Str("code", code).Msg("Execution is older than 1 hour, cleaning up")
+		} else {
+			continue
+		}
+
+		finished = append(finished, execution)
+		delete(s.executions, code)
+	}
+
+	return finished
+}
+
+// removeExecution ends the execution with the code and removes it from the
+// service without saving it.
+func (s *inMemoryService) removeExecution(code string) {
+	s.mu.Lock()
+	execution, ok := s.executions[code]
+	delete(s.executions, code)
+	s.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	execution.mu.Lock()
+	defer execution.mu.Unlock()
+	if err := execution.end(); err != nil {
+		log.Error().Err(err).Str("code", code).Msg("Failed to end execution")
+	}
+}
+
 func (s *inMemoryService) Stop() {
 	s.done <- true
 }
 
-func (s *inMemoryService) CreateExecution(ctx context.Context, quizId string, hostId string) (string, error) {
-	execution := Execution{
-		Phase:           PhaseLobby,
-		CurrentQuestion: 0,
-		CreatedAt:       time.Now(),
+func (s *inMemoryService) CreateExecution(ctx context.Context, quizId string, hostId string, settings Settings) (string, error) {
+	if err := settings.Validate(); err != nil {
+		return "", err
 	}
-	for i := range 100 {
-		code := generateCode()
-		if _, ok := s.executions[code]; !ok {
-			execution.Code = code
-			break
-		}
 
-		if i == 99 {
-			return "", errors.New("failed to generate a unique code")
-		}
+	execution := Execution{
+		Settings:         settings,
+		Phase:            PhaseLobby,
+		CurrentQuestion:  0,
+		CreatedAt:        time.Now(),
+		SkippedQuestions: map[string]bool{},
+		Tiebreaks:        map[string]bool{},
+		unsavedOptions:   map[string]bool{},
+		Seed:             rand.Int63(),
+		done:             make(chan bool, 1),
 	}
 
 	err := s.db.InTx(ctx, func(s postgres.Session) error {
@@ -100,6 +149,22 @@ func (s *inMemoryService) CreateExecution(ctx context.Context, quizId string, ho
 		}
 		execution.Questions = questions
 
+		slides, err := s.ListSlides(ctx, quizId)
+		if err != nil {
+			return err
+		}
+		execution.Slides = placeSlides(slides, questions)
+
+		sections, err := s.ListSections(ctx, quizId)
+		if err != nil {
+			return err
+		}
+		execution.Sections = sections
+
+		if settings.ShuffleQuestions {
+			shuffleQuestions(execution.Questions, execution.Seed)
+		}
+
 		host, err := s.GetUser(ctx, hostId)
 		if err != nil {
 			return err
@@ -112,21 +177,55 @@ func (s *inMemoryService) CreateExecution(ctx context.Context, quizId string, ho
 		return "", err
 	}
 
+	// Pick the code after loading the quiz, so that the executions are only
+	// locked for a short while.
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range 100 {
+		code, err := s.codes.generate()
+		if err != nil {
+			return "", fmt.Errorf("generate code: %w", err)
+		}
+		if _, ok := s.executions[code]; !ok {
+			execution.Code = code
+			break
+		}
+
+		if i == 99 {
+			return "", errors.New("failed to generate a unique code")
+		}
+	}
+
 	s.executions[execution.Code] = &execution
 	execution.Run()
 	return execution.Code, nil
 }
 
 func (s *inMemoryService) GetExecution(ctx context.Context, code string) (*Execution, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	execution, ok := s.executions[code]
 	if !ok {
-		return nil, errors.New("execution not found")
+		return nil, ErrNotFound
 	}
 	return execution, nil
 }
 
-func generateCode() string {
-	// Generate a random number between 100000 and 999999
-	num := rand.Intn(900000) + 100000
-	return strconv.Itoa(num)
+func (s *inMemoryService) ListExecutions(ctx context.Context, userID string) ([]*Execution, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	executions := []*Execution{}
+	for _, execution := range s.executions {
+		if execution.CanControl(userID) {
+			executions = append(executions, execution)
+		}
+	}
+
+	slices.SortFunc(executions, func(a, b *Execution) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	return executions, nil
 }
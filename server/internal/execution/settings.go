@@ -0,0 +1,148 @@
+package execution
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+type ScoringMode string
+
+const (
+	// ScoringModeCorrect gives one point for every correct answer.
+	ScoringModeCorrect ScoringMode = "correct"
+	// ScoringModeSpeed gives more points the faster a correct answer is given.
+	ScoringModeSpeed ScoringMode = "speed"
+)
+
+// Settings configures how a single execution of a quiz is run.
+type Settings struct {
+	ShuffleQuestions     bool        `json:"shuffleQuestions"`
+	ShuffleAnswers       bool        `json:"shuffleAnswers"`
+	ScoringMode          ScoringMode `json:"scoringMode"`
+	ShowCorrectAnswers   bool        `json:"showCorrectAnswers"`
+	TimerEnabled         bool        `json:"timerEnabled"`
+	AllowLateJoin        bool        `json:"allowLateJoin"`
+	AnonymousLeaderboard bool        `json:"anonymousLeaderboard"`
+	// AnswerChangeWindowSeconds is how long after their first answer a
+	// participant may still change it. Zero means answers are final.
+	AnswerChangeWindowSeconds uint64 `json:"answerChangeWindowSeconds"`
+	// JoinPassword must be given by participants and spectators to join, if
+	// set.
+	JoinPassword string `json:"joinPassword,omitempty"`
+	// RequireAccount only lets logged in users join, not guests.
+	RequireAccount bool `json:"requireAccount"`
+	// Teams are the names of the teams participants play in. The quiz is not
+	// played in teams if there are none.
+	Teams       []string    `json:"teams,omitempty"`
+	TeamScoring TeamScoring `json:"teamScoring"`
+	// TeamHuddle scores every member of a team on the answer most of the team
+	// voted for, instead of on their own answer.
+	TeamHuddle bool `json:"teamHuddle"`
+	// Elimination knocks out participants that answer a question wrong or
+	// not at all, until no more than Survivors remain.
+	Elimination bool `json:"elimination"`
+	// Survivors is how many participants remain when an elimination
+	// execution is over. Zero means a single winner.
+	Survivors uint64 `json:"survivors,omitempty"`
+	// CountdownSeconds is how long the question is shown before its answer
+	// options unlock. Zero unlocks them straight away.
+	CountdownSeconds uint64 `json:"countdownSeconds"`
+	// RevealSeconds is how long the answer to a finished question is shown
+	// before the leaderboard. Zero goes straight to the leaderboard.
+	RevealSeconds uint64 `json:"revealSeconds"`
+	// AutoPilot runs the execution without the host once it has started:
+	// questions finish when their time is up, or after a default time if
+	// they have no limit, the results are shown for ResultsSeconds and the
+	// next question starts on its own.
+	AutoPilot      bool   `json:"autoPilot"`
+	ResultsSeconds uint64 `json:"resultsSeconds,omitempty"`
+	// Reactions lets participants send emoji reactions to the host screen
+	// while waiting in the lobby, and Chat lets them send short messages.
+	Reactions bool `json:"reactions"`
+	Chat      bool `json:"chat"`
+}
+
+// maxPhaseSeconds limits how long the countdown, reveal and auto-pilot results
+// phases can last.
+const maxPhaseSeconds = 60
+
+// defaultResultsSeconds is how long the results are shown on auto-pilot if not
+// configured.
+const defaultResultsSeconds = 5
+
+// defaultQuestionSeconds is how long questions without a time limit are open
+// on auto-pilot, so that a participant who never answers cannot stall it.
+const defaultQuestionSeconds = 60
+
+// DefaultSettings returns the settings used when the host does not supply any.
+func DefaultSettings() Settings {
+	return Settings{
+		ScoringMode:        ScoringModeCorrect,
+		ShowCorrectAnswers: true,
+		TimerEnabled:       true,
+		AllowLateJoin:      true,
+		TeamScoring:        TeamScoringSum,
+		Reactions:          true,
+	}
+}
+
+func (s Settings) Validate() error {
+	switch s.ScoringMode {
+	case ScoringModeCorrect, ScoringModeSpeed:
+	default:
+		return fmt.Errorf("unknown scoring mode: %s", s.ScoringMode)
+	}
+
+	if len(s.Teams) == 1 {
+		return fmt.Errorf("at least two teams are required")
+	}
+	for i, team := range s.Teams {
+		if strings.TrimSpace(team) == "" {
+			return fmt.Errorf("team names cannot be empty")
+		}
+		if slices.Contains(s.Teams[:i], team) {
+			return fmt.Errorf("duplicate team: %s", team)
+		}
+	}
+
+	switch s.TeamScoring {
+	case "", TeamScoringSum, TeamScoringAverage:
+	default:
+		return fmt.Errorf("unknown team scoring: %s", s.TeamScoring)
+	}
+
+	if s.TeamHuddle && len(s.Teams) == 0 {
+		return fmt.Errorf("team huddles require teams")
+	}
+
+	if s.Survivors > 0 && !s.Elimination {
+		return fmt.Errorf("survivors require elimination")
+	}
+
+	if s.Elimination && len(s.Teams) > 0 {
+		return fmt.Errorf("elimination cannot be played in teams")
+	}
+
+	if s.CountdownSeconds > maxPhaseSeconds {
+		return fmt.Errorf("countdown cannot be longer than %d seconds", maxPhaseSeconds)
+	}
+
+	if s.RevealSeconds > maxPhaseSeconds {
+		return fmt.Errorf("reveal cannot be longer than %d seconds", maxPhaseSeconds)
+	}
+
+	if s.AutoPilot && !s.TimerEnabled {
+		return fmt.Errorf("auto-pilot requires the timer")
+	}
+
+	if s.ResultsSeconds > 0 && !s.AutoPilot {
+		return fmt.Errorf("results duration requires auto-pilot")
+	}
+
+	if s.ResultsSeconds > maxPhaseSeconds {
+		return fmt.Errorf("results cannot be shown longer than %d seconds", maxPhaseSeconds)
+	}
+
+	return nil
+}
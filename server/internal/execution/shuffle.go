@@ -0,0 +1,105 @@
+package execution
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"math/rand"
+	"slices"
+
+	"github.com/william-joh/quizzer/server/internal/quizzer"
+)
+
+// shuffleQuestions randomizes the order of the questions, using the seed so
+// that the order can be reproduced. Questions are only shuffled within their
+// section, so that the rounds of the quiz stay in order.
+func shuffleQuestions(questions []quizzer.Question, seed int64) {
+	r := rand.New(rand.NewSource(seed))
+	for start := 0; start < len(questions); {
+		end := start + 1
+		for end < len(questions) && sameSection(questions[start], questions[end]) {
+			end++
+		}
+
+		round := questions[start:end]
+		r.Shuffle(len(round), func(i, j int) {
+			round[i], round[j] = round[j], round[i]
+		})
+		start = end
+	}
+}
+
+// sameSection reports whether the questions belong to the same section, or
+// both to none.
+func sameSection(a, b quizzer.Question) bool {
+	if a.SectionID == nil || b.SectionID == nil {
+		return a.SectionID == b.SectionID
+	}
+	return *a.SectionID == *b.SectionID
+}
+
+// seedFor derives a seed from the seed of the execution and the given keys.
+// The same keys always give the same seed within an execution.
+func (e *Execution) seedFor(keys ...string) int64 {
+	return deriveSeed(e.Seed, keys...)
+}
+
+func deriveSeed(seed int64, keys ...string) int64 {
+	h := fnv.New64a()
+	binary.Write(h, binary.LittleEndian, seed)
+	for _, key := range keys {
+		h.Write([]byte(key))
+		h.Write([]byte{0})
+	}
+	return int64(h.Sum64())
+}
+
+// ShuffleFor returns the questions and their answer options in the order the
+// participant of a game that is not played live, e.g. an assignment, is shown
+// them, as the settings ask for. The order is the same every time it is asked
+// for, for the same game and participant.
+func ShuffleFor(settings Settings, gameID, participantID string, questions []quizzer.Question) []quizzer.Question {
+	e := Execution{Settings: settings, Seed: deriveSeed(0, gameID)}
+
+	shuffled := slices.Clone(questions)
+	if settings.ShuffleQuestions {
+		shuffleQuestions(shuffled, e.seedFor(participantID))
+	}
+
+	for i, q := range shuffled {
+		options := []quizzer.AnswerOption{}
+		for _, j := range e.optionOrder(participantID, q) {
+			options = append(options, q.Options[j])
+		}
+		shuffled[i].Options = options
+	}
+	return shuffled
+}
+
+// optionOrder returns the order in which the participant is shown the answer
+// options of the question, as indices into the options of the question.
+func (e *Execution) optionOrder(participantID string, q quizzer.Question) []int {
+	order := make([]int, len(q.Options))
+	for i := range order {
+		order[i] = i
+	}
+
+	if !e.Settings.ShuffleAnswers {
+		return order
+	}
+
+	r := rand.New(rand.NewSource(e.seedFor(participantID, q.ID)))
+	r.Shuffle(len(order), func(i, j int) {
+		order[i], order[j] = order[j], order[i]
+	})
+	return order
+}
+
+// participantOptions returns the answer options of the question in the order
+// they are shown to the participant.
+func (e *Execution) participantOptions(participantID string, q quizzer.Question) []option {
+	options := []option{}
+	for _, i := range e.optionOrder(participantID, q) {
+		options = append(options, toOption(q.Options[i]))
+	}
+	return options
+}
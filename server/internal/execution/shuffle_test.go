@@ -0,0 +1,224 @@
+package execution
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/william-joh/quizzer/server/internal/quizzer"
+)
+
+// testQuestions returns questions with the given number of answer options
+// each, of which the first is correct.
+func testQuestions(n, nrOptions int) []quizzer.Question {
+	questions := []quizzer.Question{}
+	for i := range n {
+		q := quizzer.Question{ID: fmt.Sprintf("q%d", i), Question: fmt.Sprintf("question %d", i)}
+		for j := range nrOptions {
+			q.Options = append(q.Options, quizzer.AnswerOption{
+				ID:        fmt.Sprintf("q%d-o%d", i, j),
+				Text:      fmt.Sprintf("option %d", j),
+				IsCorrect: j == 0,
+			})
+		}
+		questions = append(questions, q)
+	}
+	return questions
+}
+
+func TestParticipantOptions(t *testing.T) {
+	q := testQuestions(1, 6)[0]
+
+	tests := []struct {
+		name     string
+		settings Settings
+		shuffled bool
+	}{
+		{
+			name:     "in quiz order",
+			settings: Settings{},
+		},
+		{
+			name:     "shuffled",
+			settings: Settings{ShuffleAnswers: true},
+			shuffled: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestExecution(tt.settings, q)
+			e.Seed = 42
+
+			orders := map[string]bool{}
+			for i := range 10 {
+				participantID := fmt.Sprintf("p%d", i)
+				order := e.optionOrder(participantID, q)
+				require.ElementsMatch(t, []int{0, 1, 2, 3, 4, 5}, order)
+				require.Equal(t, order, e.optionOrder(participantID, q), "order must be the same every time")
+				orders[fmt.Sprint(order)] = true
+
+				// The options shown map back to the options of the
+				// question through the order.
+				options := e.participantOptions(participantID, q)
+				require.Len(t, options, len(order))
+				for j, o := range options {
+					require.Equal(t, toOption(q.Options[order[j]]), o)
+				}
+			}
+
+			if tt.shuffled {
+				require.Greater(t, len(orders), 1, "participants must get different orders")
+			} else {
+				require.Equal(t, map[string]bool{"[0 1 2 3 4 5]": true}, orders)
+			}
+		})
+	}
+}
+
+func TestShuffleFor(t *testing.T) {
+	questions := testQuestions(8, 4)
+
+	tests := []struct {
+		name     string
+		settings Settings
+	}{
+		{
+			name:     "in quiz order",
+			settings: Settings{},
+		},
+		{
+			name:     "shuffled questions",
+			settings: Settings{ShuffleQuestions: true},
+		},
+		{
+			name:     "shuffled answers",
+			settings: Settings{ShuffleAnswers: true},
+		},
+		{
+			name:     "shuffled questions and answers",
+			settings: Settings{ShuffleQuestions: true, ShuffleAnswers: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := testQuestions(8, 4)
+			shuffled := ShuffleFor(tt.settings, "game-id", "p1", questions)
+			require.Equal(t, original, questions, "questions must not be changed")
+			require.Equal(t, shuffled, ShuffleFor(tt.settings, "game-id", "p1", questions), "order must be the same every time")
+
+			if !tt.settings.ShuffleQuestions && !tt.settings.ShuffleAnswers {
+				require.Equal(t, questions, shuffled)
+				return
+			}
+
+			byID := map[string]quizzer.Question{}
+			for _, q := range questions {
+				byID[q.ID] = q
+			}
+
+			var questionsMoved, optionsMoved bool
+			require.Len(t, shuffled, len(questions))
+			for i, q := range shuffled {
+				want, ok := byID[q.ID]
+				require.True(t, ok)
+				delete(byID, q.ID)
+				questionsMoved = questionsMoved || q.ID != questions[i].ID
+
+				// The options are the same, and the correct one is still
+				// correct.
+				require.ElementsMatch(t, want.Options, q.Options)
+				for j, o := range q.Options {
+					require.Equal(t, want.IsCorrect(o.ID), o.IsCorrect)
+					optionsMoved = optionsMoved || o.ID != want.Options[j].ID
+				}
+			}
+			require.Empty(t, byID)
+			require.Equal(t, tt.settings.ShuffleQuestions, questionsMoved)
+			require.Equal(t, tt.settings.ShuffleAnswers, optionsMoved)
+		})
+	}
+
+	t.Run("participants get different orders", func(t *testing.T) {
+		settings := Settings{ShuffleQuestions: true, ShuffleAnswers: true}
+		require.NotEqual(t,
+			ShuffleFor(settings, "game-id", "p1", questions),
+			ShuffleFor(settings, "game-id", "p2", questions),
+		)
+	})
+
+	t.Run("games get different orders", func(t *testing.T) {
+		settings := Settings{ShuffleQuestions: true, ShuffleAnswers: true}
+		require.NotEqual(t,
+			ShuffleFor(settings, "game-1", "p1", questions),
+			ShuffleFor(settings, "game-2", "p1", questions),
+		)
+	})
+}
+
+func TestShuffleQuestions(t *testing.T) {
+	// inSections returns questions in the sections, in order. An empty
+	// section name puts a question in no section.
+	inSections := func(sections ...string) []quizzer.Question {
+		questions := testQuestions(len(sections), 2)
+		for i, s := range sections {
+			if s != "" {
+				questions[i].SectionID = &s
+			}
+		}
+		return questions
+	}
+
+	tests := []struct {
+		name      string
+		questions []quizzer.Question
+	}{
+		{
+			name:      "without sections",
+			questions: inSections("", "", "", "", "", "", "", ""),
+		},
+		{
+			name:      "in sections",
+			questions: inSections("a", "a", "a", "a", "b", "b", "b", "b"),
+		},
+		{
+			name:      "sections between questions without one",
+			questions: inSections("", "", "", "a", "a", "a", "a", "", "", "", "b", "b", "b"),
+		},
+		{
+			name:      "single question sections",
+			questions: inSections("a", "b", "c", "d"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sectionOf := func(q quizzer.Question) string {
+				if q.SectionID == nil {
+					return ""
+				}
+				return *q.SectionID
+			}
+
+			shuffled := slices.Clone(tt.questions)
+			shuffleQuestions(shuffled, 42)
+			again := slices.Clone(tt.questions)
+			shuffleQuestions(again, 42)
+			require.Equal(t, shuffled, again, "order must be the same for the same seed")
+			require.ElementsMatch(t, tt.questions, shuffled)
+
+			// Every position keeps a question of the same section, so the
+			// rounds stay in order.
+			for i := range shuffled {
+				require.Equal(t, sectionOf(tt.questions[i]), sectionOf(shuffled[i]))
+			}
+		})
+	}
+
+	t.Run("questions are shuffled within their section", func(t *testing.T) {
+		questions := inSections("a", "a", "a", "a", "a", "a", "b", "b", "b", "b", "b", "b")
+		shuffled := slices.Clone(questions)
+		shuffleQuestions(shuffled, 42)
+		require.NotEqual(t, questions[:6], shuffled[:6])
+		require.NotEqual(t, questions[6:], shuffled[6:])
+	})
+}
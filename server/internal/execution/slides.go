@@ -0,0 +1,70 @@
+package execution
+
+import (
+	"slices"
+
+	"github.com/william-joh/quizzer/server/internal/quizzer"
+)
+
+// PhaseSlide shows an information slide between questions. Slides are not
+// answered and not scored.
+const PhaseSlide Phase = "slide"
+
+// Slide is a slide of the quiz and where it is shown in the execution.
+type Slide struct {
+	quizzer.Slide
+	// Before is the number of questions that are shown before the slide.
+	Before int `json:"before"`
+}
+
+// placeSlides returns the slides ordered as they are shown, each placed
+// between the questions it sits between in the quiz. The questions must not be
+// shuffled yet, so that shuffling keeps the slides at their position in the
+// sequence.
+func placeSlides(slides []quizzer.Slide, questions []quizzer.Question) []Slide {
+	placed := []Slide{}
+	for _, s := range slides {
+		before := 0
+		for _, q := range questions {
+			if q.Index < s.Index {
+				before++
+			}
+		}
+		placed = append(placed, Slide{Slide: s, Before: before})
+	}
+
+	slices.SortStableFunc(placed, func(a, b Slide) int { return a.Index - b.Index })
+	return placed
+}
+
+// hasPendingSlide returns whether a slide is due before the current question.
+func (e *Execution) hasPendingSlide() bool {
+	return e.CurrentSlide < len(e.Slides) && e.Slides[e.CurrentSlide].Before <= e.CurrentQuestion
+}
+
+// showSlide moves the execution into the slide phase for the pending slide. On
+// auto-pilot, the slide is only shown for a while.
+func (e *Execution) showSlide() {
+	e.Phase = PhaseSlide
+	e.PhaseDeadline = e.autoPilotDeadline()
+}
+
+// getSlidePayload returns the state shown to everyone while a slide is shown.
+func (e *Execution) getSlidePayload() (interface{}, error) {
+	s := e.Slides[e.CurrentSlide]
+	return struct {
+		Phase    string  `json:"phase"`
+		Title    string  `json:"title"`
+		Body     string  `json:"body"`
+		ImageURL *string `json:"imageUrl,omitempty"`
+		VideoURL *string `json:"videoUrl,omitempty"`
+		TimeLeft uint64  `json:"timeLeft"`
+	}{
+		Phase:    string(e.Phase),
+		Title:    s.Title,
+		Body:     s.Body,
+		ImageURL: s.ImageURL,
+		VideoURL: s.VideoURL,
+		TimeLeft: e.phaseTimeLeft(),
+	}, nil
+}
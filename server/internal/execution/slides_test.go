@@ -0,0 +1,69 @@
+package execution
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/william-joh/quizzer/server/internal/quizzer"
+)
+
+func TestPlaceSlides(t *testing.T) {
+	// The quiz is: slide 0, question 1, question 2, slide 3, question 4,
+	// slide 5.
+	questions := []quizzer.Question{{ID: "q1", Index: 1}, {ID: "q2", Index: 2}, {ID: "q4", Index: 4}}
+
+	tests := []struct {
+		name      string
+		slides    []quizzer.Slide
+		questions []quizzer.Question
+		want      []Slide
+	}{
+		{
+			name:      "no slides",
+			questions: questions,
+			want:      []Slide{},
+		},
+		{
+			name:      "before, between and after the questions",
+			slides:    []quizzer.Slide{{ID: "s0", Index: 0}, {ID: "s3", Index: 3}, {ID: "s5", Index: 5}},
+			questions: questions,
+			want: []Slide{
+				{Slide: quizzer.Slide{ID: "s0", Index: 0}, Before: 0},
+				{Slide: quizzer.Slide{ID: "s3", Index: 3}, Before: 2},
+				{Slide: quizzer.Slide{ID: "s5", Index: 5}, Before: 3},
+			},
+		},
+		{
+			name:      "out of order",
+			slides:    []quizzer.Slide{{ID: "s5", Index: 5}, {ID: "s0", Index: 0}, {ID: "s3", Index: 3}},
+			questions: questions,
+			want: []Slide{
+				{Slide: quizzer.Slide{ID: "s0", Index: 0}, Before: 0},
+				{Slide: quizzer.Slide{ID: "s3", Index: 3}, Before: 2},
+				{Slide: quizzer.Slide{ID: "s5", Index: 5}, Before: 3},
+			},
+		},
+		{
+			name:      "consecutive slides",
+			slides:    []quizzer.Slide{{ID: "s3", Index: 3}, {ID: "s4", Index: 4}},
+			questions: []quizzer.Question{{ID: "q1", Index: 1}, {ID: "q2", Index: 2}, {ID: "q5", Index: 5}},
+			want: []Slide{
+				{Slide: quizzer.Slide{ID: "s3", Index: 3}, Before: 2},
+				{Slide: quizzer.Slide{ID: "s4", Index: 4}, Before: 2},
+			},
+		},
+		{
+			name:   "no questions",
+			slides: []quizzer.Slide{{ID: "s0", Index: 0}, {ID: "s1", Index: 1}},
+			want: []Slide{
+				{Slide: quizzer.Slide{ID: "s0", Index: 0}, Before: 0},
+				{Slide: quizzer.Slide{ID: "s1", Index: 1}, Before: 0},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, placeSlides(tt.slides, tt.questions))
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package execution
+
+// RoleSpectator is the role to give in the join message to follow an
+// execution without taking part in it, e.g. on a projector.
+const RoleSpectator = "spectator"
+
+// getSpectatorPayload returns the state shown to spectators. It is the same as
+// the state shown to the host, but without anything that lets them control the
+// execution, and the correct answers are only revealed to them like they are to
+// the participants.
+func (e *Execution) getSpectatorPayload() (interface{}, error) {
+	switch e.Phase {
+	case PhaseLobby:
+		return e.getSpectatorLobbyPayload()
+	case PhaseReveal:
+		return e.getRevealPayload(e.Settings.ShowCorrectAnswers)
+	default:
+		return e.getHostPayload()
+	}
+}
+
+func (e *Execution) getSpectatorLobbyPayload() (interface{}, error) {
+
+	payload := struct {
+		QuizTitle        string             `json:"quizTitle"`
+		HostName         string             `json:"hostName"`
+		IsHost           bool               `json:"isHost"`
+		IsSpectator      bool               `json:"isSpectator"`
+		ParticipantNames []string           `json:"participantNames"`
+		Participants     []lobbyParticipant `json:"participants"`
+		Teams            []lobbyTeam        `json:"teams,omitempty"`
+		Phase            string             `json:"phase"`
+	}{
+		QuizTitle:        e.Quiz.Title,
+		HostName:         e.Host.Username,
+		IsHost:           false,
+		IsSpectator:      true,
+		Phase:            string(e.Phase),
+		ParticipantNames: []string{},
+		Participants:     e.getLobbyParticipants(),
+		Teams:            e.getLobbyTeams(),
+	}
+
+	for _, p := range e.Participants {
+		payload.ParticipantNames = append(payload.ParticipantNames, p.Name)
+	}
+
+	return payload, nil
+}
@@ -0,0 +1,70 @@
+package execution
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/william-joh/quizzer/server/internal/quizzer"
+)
+
+func TestSpectatorPayload(t *testing.T) {
+	question := quizzer.Question{ID: "q1", Question: "q1", Options: []quizzer.AnswerOption{
+		{ID: "o1", Text: "a", IsCorrect: true},
+		{ID: "o2", Text: "b"},
+	}}
+
+	tests := []struct {
+		name     string
+		settings Settings
+		// finish finishes the question before the payload is taken.
+		finish bool
+		want   any
+	}{
+		{
+			name:     "open question",
+			settings: Settings{RevealSeconds: 5, ShowCorrectAnswers: true},
+		},
+		{
+			name:     "finished question",
+			settings: Settings{RevealSeconds: 5, ShowCorrectAnswers: true},
+			finish:   true,
+			want:     []any{map[string]any{"id": "o1", "text": "a"}},
+		},
+		{
+			name:     "finished question without correct answers",
+			settings: Settings{RevealSeconds: 5},
+			finish:   true,
+			want:     []any{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestExecution(tt.settings, question)
+			require.NoError(t, e.HandleMessage(&testConn{}, Identity{ID: e.Host.ID}, Message{Type: "Join"}))
+			require.NoError(t, e.HandleMessage(&testConn{}, Identity{ID: "p1", Name: "p1", IsGuest: true}, Message{Type: "Join"}))
+			require.NoError(t, e.start())
+			if tt.finish {
+				require.NoError(t, e.finish())
+				require.Equal(t, PhaseReveal, e.Phase)
+			}
+
+			payload, err := e.getSpectatorPayload()
+			require.NoError(t, err)
+			b, err := json.Marshal(payload)
+			require.NoError(t, err)
+			var got map[string]any
+			require.NoError(t, json.Unmarshal(b, &got))
+			require.Equal(t, tt.want, got["correctOptions"])
+
+			// The host always sees the correct answers.
+			if tt.finish {
+				payload, err := e.getHostPayload()
+				require.NoError(t, err)
+				b, err := json.Marshal(payload)
+				require.NoError(t, err)
+				require.Contains(t, string(b), `"correctOptions":[{"id":"o1","text":"a"}]`)
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package execution
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/william-joh/quizzer/server/internal/quizzer"
+)
+
+type TeamScoring string
+
+const (
+	// TeamScoringSum ranks teams by the sum of the scores of their members.
+	TeamScoringSum TeamScoring = "sum"
+	// TeamScoringAverage ranks teams by the average score of their members,
+	// so that smaller teams are not at a disadvantage.
+	TeamScoringAverage TeamScoring = "average"
+)
+
+type lobbyTeam struct {
+	Name    string   `json:"name"`
+	Members []string `json:"members"`
+}
+
+type teamResult struct {
+	Name      string  `json:"name"`
+	NrMembers int     `json:"nrMembers"`
+	Score     float64 `json:"score"`
+}
+
+func (e *Execution) teamsEnabled() bool {
+	return len(e.Settings.Teams) > 0
+}
+
+// smallestTeam returns the team with the fewest members, which participants
+// are put in when they join.
+func (e *Execution) smallestTeam() string {
+	if !e.teamsEnabled() {
+		return ""
+	}
+
+	sizes := map[string]int{}
+	for _, p := range e.Participants {
+		sizes[p.Team]++
+	}
+
+	smallest := e.Settings.Teams[0]
+	for _, team := range e.Settings.Teams[1:] {
+		if sizes[team] < sizes[smallest] {
+			smallest = team
+		}
+	}
+	return smallest
+}
+
+func (e *Execution) handleJoinTeamMsg(conn Conn, msg Message) error {
+	p, ok := e.getParticipantByConn(conn)
+	if !ok {
+		log.Error().Msg("Only participants can join a team")
+		return fmt.Errorf("only participants can join a team")
+	}
+
+	if !e.teamsEnabled() {
+		log.Error().Msg("Quiz is not played in teams")
+		return fmt.Errorf("quiz is not played in teams")
+	}
+
+	if e.Phase != PhaseLobby {
+		log.Error().Msg("Teams can only be changed in the lobby")
+		return fmt.Errorf("teams can only be changed in the lobby")
+	}
+
+	data, ok := msg.Data.(map[string]interface{})
+	if !ok {
+		log.Error().Msgf("Failed to parse data, expected map[string]string, got %T", msg.Data)
+		return fmt.Errorf("parse data: expected map[string]string, got %T", msg.Data)
+	}
+
+	team, _ := data["team"].(string)
+	if !slices.Contains(e.Settings.Teams, team) {
+		log.Error().Str("team", team).Msg("Unknown team")
+		return fmt.Errorf("unknown team: %s", team)
+	}
+	p.Team = team
+
+	// Broadcast the new quiz state
+	if err := e.broadcastQuizState(); err != nil {
+		log.Error().Err(err).Msg("Failed to broadcast quiz state")
+		return fmt.Errorf("broadcast quiz state: %w", err)
+	}
+
+	return nil
+}
+
+// getLobbyTeams returns the teams with the names of their members.
+func (e *Execution) getLobbyTeams() []lobbyTeam {
+	if !e.teamsEnabled() {
+		return nil
+	}
+
+	teams := []lobbyTeam{}
+	for _, team := range e.Settings.Teams {
+		t := lobbyTeam{Name: team, Members: []string{}}
+		for _, p := range e.Participants {
+			if p.Team == team {
+				t.Members = append(t.Members, p.Name)
+			}
+		}
+		teams = append(teams, t)
+	}
+	return teams
+}
+
+// teamVotes returns how many members of the team have chosen each answer
+// option of the question, including members that left since.
+func (e *Execution) teamVotes(q quizzer.Question, team string) map[string]int {
+	votes := map[string]int{}
+	for _, p := range e.allParticipants() {
+		if answer, ok := p.Answers[q.ID]; ok && p.Team == team {
+			votes[answer]++
+		}
+	}
+	return votes
+}
+
+// huddleAnswer returns the answer of the team to the question when the team
+// huddles: the answer option most of its members voted for. A tie goes to the
+// option that was voted for first. The time left is that of the last vote for
+// the option, when the team settled on it.
+func (e *Execution) huddleAnswer(q quizzer.Question, team string) (string, time.Duration, bool) {
+	votes := e.teamVotes(q, team)
+	if len(votes) == 0 {
+		return "", 0, false
+	}
+
+	firstVote := map[string]time.Time{}
+	timeLeft := map[string]time.Duration{}
+	for _, p := range e.allParticipants() {
+		answer, ok := p.Answers[q.ID]
+		if !ok || p.Team != team {
+			continue
+		}
+
+		if first, ok := firstVote[answer]; !ok || p.answeredAt[q.ID].Before(first) {
+			firstVote[answer] = p.answeredAt[q.ID]
+		}
+		if left, ok := timeLeft[answer]; !ok || p.answerTimeLeft[q.ID] < left {
+			timeLeft[answer] = p.answerTimeLeft[q.ID]
+		}
+	}
+
+	var best string
+	for answer, n := range votes {
+		if best == "" || n > votes[best] || (n == votes[best] && firstVote[answer].Before(firstVote[best])) {
+			best = answer
+		}
+	}
+
+	return best, timeLeft[best], true
+}
+
+// getTeamResults returns the ranking of the teams on the finished questions.
+func (e *Execution) getTeamResults() []teamResult {
+	if !e.teamsEnabled() {
+		return nil
+	}
+
+	participants := e.getResults()
+	results := []teamResult{}
+	for _, team := range e.Settings.Teams {
+		result := teamResult{Name: team}
+		for _, p := range participants {
+			if p.Team != team {
+				continue
+			}
+
+			result.Score += float64(p.Score)
+			result.NrMembers++
+		}
+
+		if e.Settings.TeamScoring == TeamScoringAverage && result.NrMembers > 0 {
+			result.Score /= float64(result.NrMembers)
+		}
+
+		results = append(results, result)
+	}
+
+	slices.SortStableFunc(results, func(a, b teamResult) int {
+		return cmp.Compare(b.Score, a.Score)
+	})
+
+	return results
+}
+
+// teamRank returns the place of the team in the ranking, starting at 1.
+func (e *Execution) teamRank(team string) int {
+	return slices.IndexFunc(e.getTeamResults(), func(r teamResult) bool {
+		return r.Name == team
+	}) + 1
+}
@@ -0,0 +1,146 @@
+package execution
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/william-joh/quizzer/server/internal/quizzer"
+)
+
+func TestSmallestTeam(t *testing.T) {
+	tests := []struct {
+		name    string
+		teams   []string
+		members []string
+		want    string
+	}{
+		{
+			name: "without teams",
+			want: "",
+		},
+		{
+			name:  "no members yet",
+			teams: []string{"red", "blue"},
+			want:  "red",
+		},
+		{
+			name:    "fewest members",
+			teams:   []string{"red", "blue", "green"},
+			members: []string{"red", "blue", "red", "green"},
+			want:    "blue",
+		},
+		{
+			name:    "tie goes to the first team",
+			teams:   []string{"red", "blue", "green"},
+			members: []string{"red", "blue", "green"},
+			want:    "red",
+		},
+		{
+			name:    "empty team",
+			teams:   []string{"red", "blue", "green"},
+			members: []string{"red", "green", "green"},
+			want:    "blue",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestExecution(Settings{Teams: tt.teams})
+			for _, team := range tt.members {
+				e.Participants = append(e.Participants, Participant{Team: team})
+			}
+
+			require.Equal(t, tt.want, e.smallestTeam())
+		})
+	}
+}
+
+func TestHuddleAnswer(t *testing.T) {
+	q := quizzer.Question{ID: "q1", Options: []quizzer.AnswerOption{{ID: "o1"}, {ID: "o2"}, {ID: "o3"}}}
+	start := time.Now()
+
+	// vote is the answer of a member of a team, given after some time with
+	// some time left.
+	type vote struct {
+		team     string
+		answer   string
+		after    time.Duration
+		timeLeft time.Duration
+	}
+
+	tests := []struct {
+		name     string
+		votes    []vote
+		team     string
+		answer   string
+		timeLeft time.Duration
+		ok       bool
+	}{
+		{
+			name: "no votes",
+			team: "red",
+		},
+		{
+			name: "only votes of other teams",
+			votes: []vote{
+				{team: "blue", answer: "o1", after: time.Second, timeLeft: 9 * time.Second},
+			},
+			team: "red",
+		},
+		{
+			name: "single vote",
+			votes: []vote{
+				{team: "red", answer: "o2", after: time.Second, timeLeft: 9 * time.Second},
+			},
+			team:     "red",
+			answer:   "o2",
+			timeLeft: 9 * time.Second,
+			ok:       true,
+		},
+		{
+			name: "majority",
+			votes: []vote{
+				{team: "red", answer: "o1", after: time.Second, timeLeft: 9 * time.Second},
+				{team: "red", answer: "o2", after: 2 * time.Second, timeLeft: 8 * time.Second},
+				{team: "red", answer: "o2", after: 4 * time.Second, timeLeft: 6 * time.Second},
+				{team: "blue", answer: "o1", after: 5 * time.Second, timeLeft: 5 * time.Second},
+				{team: "blue", answer: "o1", after: 5 * time.Second, timeLeft: 5 * time.Second},
+			},
+			team:     "red",
+			answer:   "o2",
+			timeLeft: 6 * time.Second,
+			ok:       true,
+		},
+		{
+			name: "tie goes to the first vote",
+			votes: []vote{
+				{team: "red", answer: "o3", after: 3 * time.Second, timeLeft: 7 * time.Second},
+				{team: "red", answer: "o1", after: time.Second, timeLeft: 9 * time.Second},
+				{team: "red", answer: "o3", after: 5 * time.Second, timeLeft: 5 * time.Second},
+				{team: "red", answer: "o1", after: 6 * time.Second, timeLeft: 4 * time.Second},
+			},
+			team:     "red",
+			answer:   "o1",
+			timeLeft: 4 * time.Second,
+			ok:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestExecution(Settings{Teams: []string{"red", "blue"}, TeamHuddle: true}, q)
+			for _, v := range tt.votes {
+				e.Participants = append(e.Participants, Participant{
+					Team:           v.team,
+					Answers:        map[string]string{q.ID: v.answer},
+					answeredAt:     map[string]time.Time{q.ID: start.Add(v.after)},
+					answerTimeLeft: map[string]time.Duration{q.ID: v.timeLeft},
+				})
+			}
+
+			answer, timeLeft, ok := e.huddleAnswer(q, tt.team)
+			require.Equal(t, tt.ok, ok)
+			require.Equal(t, tt.answer, answer)
+			require.Equal(t, tt.timeLeft, timeLeft)
+		})
+	}
+}
@@ -22,8 +22,8 @@ func (m *ExecutionService) Stop() {
 	m.Called()
 }
 
-func (m *ExecutionService) CreateExecution(ctx context.Context, quizId string, hostId string) (string, error) {
-	args := m.Called(ctx, quizId, hostId)
+func (m *ExecutionService) CreateExecution(ctx context.Context, quizId string, hostId string, settings execution.Settings) (string, error) {
+	args := m.Called(ctx, quizId, hostId, settings)
 	return args.String(0), args.Error(1)
 }
 
@@ -59,3 +59,11 @@ func (m *ExecutionService) GetExecution(ctx context.Context, code string) (*exec
 	}
 	return args.Get(0).(*execution.Execution), args.Error(1)
 }
+
+func (m *ExecutionService) ListExecutions(ctx context.Context, userID string) ([]*execution.Execution, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*execution.Execution), args.Error(1)
+}
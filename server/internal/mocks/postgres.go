@@ -2,6 +2,7 @@ package mocks
 
 import (
 	"context"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 	"github.com/william-joh/quizzer/server/internal/postgres"
@@ -60,6 +61,56 @@ func (m *Session) DeleteQuestion(ctx context.Context, id string) error {
 	return args.Error(0)
 }
 
+func (m *Session) CreateSlide(ctx context.Context, slide quizzer.Slide) error {
+	args := m.Called(ctx, slide)
+	return args.Error(0)
+}
+
+func (m *Session) GetSlide(ctx context.Context, id string) (quizzer.Slide, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(quizzer.Slide), args.Error(1)
+}
+
+func (m *Session) ListSlides(ctx context.Context, quizID string) ([]quizzer.Slide, error) {
+	args := m.Called(ctx, quizID)
+	return args.Get(0).([]quizzer.Slide), args.Error(1)
+}
+
+func (m *Session) UpdateSlide(ctx context.Context, slide quizzer.Slide) error {
+	args := m.Called(ctx, slide)
+	return args.Error(0)
+}
+
+func (m *Session) DeleteSlide(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *Session) CreateSection(ctx context.Context, section quizzer.Section) error {
+	args := m.Called(ctx, section)
+	return args.Error(0)
+}
+
+func (m *Session) GetSection(ctx context.Context, id string) (quizzer.Section, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(quizzer.Section), args.Error(1)
+}
+
+func (m *Session) ListSections(ctx context.Context, quizID string) ([]quizzer.Section, error) {
+	args := m.Called(ctx, quizID)
+	return args.Get(0).([]quizzer.Section), args.Error(1)
+}
+
+func (m *Session) UpdateSection(ctx context.Context, section quizzer.Section) error {
+	args := m.Called(ctx, section)
+	return args.Error(0)
+}
+
+func (m *Session) DeleteSection(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
 func (m *Session) CreateUser(ctx context.Context, id, username, password string) error {
 	args := m.Called(ctx, id, username, password)
 	return args.Error(0)
@@ -109,3 +160,83 @@ func (m *Session) GetAuthSession(ctx context.Context, sessionID string) (string,
 	args := m.Called(ctx, sessionID)
 	return args.Get(0).(string), args.Error(1)
 }
+
+func (m *Session) CreateGame(ctx context.Context, game quizzer.Game) error {
+	args := m.Called(ctx, game)
+	return args.Error(0)
+}
+
+func (m *Session) GetGame(ctx context.Context, id string) (quizzer.Game, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(quizzer.Game), args.Error(1)
+}
+
+func (m *Session) ListGames(ctx context.Context, hostID string) ([]quizzer.Game, error) {
+	args := m.Called(ctx, hostID)
+	return args.Get(0).([]quizzer.Game), args.Error(1)
+}
+
+func (m *Session) CreateGameParticipant(ctx context.Context, participant quizzer.GameParticipant) error {
+	args := m.Called(ctx, participant)
+	return args.Error(0)
+}
+
+func (m *Session) GetGameParticipant(ctx context.Context, gameID, userID string) (quizzer.GameParticipant, error) {
+	args := m.Called(ctx, gameID, userID)
+	return args.Get(0).(quizzer.GameParticipant), args.Error(1)
+}
+
+func (m *Session) ListGameParticipants(ctx context.Context, gameID string) ([]quizzer.GameParticipant, error) {
+	args := m.Called(ctx, gameID)
+	return args.Get(0).([]quizzer.GameParticipant), args.Error(1)
+}
+
+func (m *Session) UpdateGameParticipant(ctx context.Context, participant quizzer.GameParticipant) error {
+	args := m.Called(ctx, participant)
+	return args.Error(0)
+}
+
+func (m *Session) SaveGameAnswer(ctx context.Context, answer quizzer.GameAnswer) error {
+	args := m.Called(ctx, answer)
+	return args.Error(0)
+}
+
+func (m *Session) ListGameAnswers(ctx context.Context, participantIDs ...string) ([]quizzer.GameAnswer, error) {
+	args := m.Called(ctx, participantIDs)
+	return args.Get(0).([]quizzer.GameAnswer), args.Error(1)
+}
+
+func (m *Session) CreateScheduledGame(ctx context.Context, game quizzer.ScheduledGame) error {
+	args := m.Called(ctx, game)
+	return args.Error(0)
+}
+
+func (m *Session) GetScheduledGame(ctx context.Context, id string) (quizzer.ScheduledGame, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(quizzer.ScheduledGame), args.Error(1)
+}
+
+func (m *Session) LockScheduledGame(ctx context.Context, id string) (quizzer.ScheduledGame, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(quizzer.ScheduledGame), args.Error(1)
+}
+
+func (m *Session) ListScheduledGames(ctx context.Context, hostID string) ([]quizzer.ScheduledGame, error) {
+	args := m.Called(ctx, hostID)
+	return args.Get(0).([]quizzer.ScheduledGame), args.Error(1)
+}
+
+func (m *Session) ListDueScheduledGames(ctx context.Context, at time.Time) ([]quizzer.ScheduledGame, error) {
+	args := m.Called(ctx, at)
+	return args.Get(0).([]quizzer.ScheduledGame), args.Error(1)
+}
+
+func (m *Session) UpdateScheduledGame(ctx context.Context, game quizzer.ScheduledGame) error {
+	args := m.Called(ctx, game)
+	return args.Error(0)
+}
+
+func (m *Session) DeleteScheduledGame(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
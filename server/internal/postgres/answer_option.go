@@ -0,0 +1,87 @@
+package postgres
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/rs/zerolog/log"
+	"github.com/william-joh/quizzer/server/internal/quizzer"
+)
+
+func (s *session) saveAnswerOptions(ctx context.Context, questionID string, options []quizzer.AnswerOption) error {
+	log.Debug().Str("questionID", questionID).Int("nrOptions", len(options)).Msg("saving answer options")
+
+	// Remove options that are no longer part of the question, and move the
+	// remaining ones out of the way so they can be reordered without
+	// violating the unique index constraint.
+	ids := []string{}
+	for _, o := range options {
+		ids = append(ids, o.ID)
+	}
+
+	sql, args, err := psql().Delete("answer_options").
+		Where(sq.Eq{"question_id": questionID}).
+		Where(sq.NotEq{"id": ids}).ToSql()
+	if err != nil {
+		return err
+	}
+	if _, err := s.conn.Exec(ctx, sql, args...); err != nil {
+		return err
+	}
+
+	sql, args, err = psql().Update("answer_options").
+		Set("index", sq.Expr("-index - 1")).
+		Where(sq.Eq{"question_id": questionID}).ToSql()
+	if err != nil {
+		return err
+	}
+	if _, err := s.conn.Exec(ctx, sql, args...); err != nil {
+		return err
+	}
+
+	for i, o := range options {
+		sql, args, err := psql().Insert("answer_options").
+			Columns("id", "question_id", "index", "text", "image_url", "audio_url", "is_correct").
+			Values(o.ID, questionID, i, o.Text, o.ImageURL, o.AudioURL, o.IsCorrect).
+			Suffix(`ON CONFLICT (id) DO UPDATE SET index = EXCLUDED.index, text = EXCLUDED.text, image_url = EXCLUDED.image_url, audio_url = EXCLUDED.audio_url, is_correct = EXCLUDED.is_correct`).
+			ToSql()
+		if err != nil {
+			return err
+		}
+
+		if _, err := s.conn.Exec(ctx, sql, args...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *session) listAnswerOptions(ctx context.Context, questionIDs ...string) (map[string][]quizzer.AnswerOption, error) {
+	log.Debug().Strs("questionIDs", questionIDs).Msg("listing answer options")
+
+	sql, args, err := psql().Select("id", "question_id", "index", "text", "image_url", "audio_url", "is_correct").
+		From("answer_options").
+		Where(sq.Eq{"question_id": questionIDs}).
+		OrderBy("question_id", "index").ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.conn.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	options := map[string][]quizzer.AnswerOption{}
+	for rows.Next() {
+		var o quizzer.AnswerOption
+		if err := rows.Scan(&o.ID, &o.QuestionID, &o.Index, &o.Text, &o.ImageURL, &o.AudioURL, &o.IsCorrect); err != nil {
+			return nil, err
+		}
+		options[o.QuestionID] = append(options[o.QuestionID], o)
+	}
+
+	return options, rows.Err()
+}
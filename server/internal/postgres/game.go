@@ -0,0 +1,226 @@
+package postgres
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog/log"
+	"github.com/william-joh/quizzer/server/internal/quizzer"
+)
+
+func (s *session) CreateGame(ctx context.Context, game quizzer.Game) error {
+	log.Debug().Str("id", game.ID).Str("quizID", game.QuizID).Str("hostID", game.HostID).Str("mode", string(game.Mode)).Msg("creating game")
+
+	settings := string(game.Settings)
+	if settings == "" {
+		settings = "{}"
+	}
+
+	sql, args, err := psql().Insert("games").
+		Columns("id", "quiz_id", "host_id", "mode", "settings", "opens_at", "closes_at").
+		Values(game.ID, game.QuizID, game.HostID, game.Mode, settings, game.OpensAt, game.ClosesAt).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = s.conn.Exec(ctx, sql, args...)
+	return err
+}
+
+func (s *session) GetGame(ctx context.Context, id string) (quizzer.Game, error) {
+	log.Debug().Str("id", id).Msg("getting game")
+
+	sql, args, err := psql().Select("id", "quiz_id", "host_id", "mode", "settings", "opens_at", "closes_at").
+		From("games").
+		Where(sq.Eq{"id": id}).ToSql()
+	if err != nil {
+		return quizzer.Game{}, err
+	}
+
+	return scanGame(s.conn.QueryRow(ctx, sql, args...))
+}
+
+// ListGames returns the games hosted by the user, newest first.
+func (s *session) ListGames(ctx context.Context, hostID string) ([]quizzer.Game, error) {
+	log.Debug().Str("hostID", hostID).Msg("listing games")
+
+	sql, args, err := psql().Select("id", "quiz_id", "host_id", "mode", "settings", "opens_at", "closes_at").
+		From("games").
+		Where(sq.Eq{"host_id": hostID}).
+		OrderBy("opens_at DESC").ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.conn.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	games := []quizzer.Game{}
+	for rows.Next() {
+		game, err := scanGame(rows)
+		if err != nil {
+			return nil, err
+		}
+		games = append(games, game)
+	}
+
+	return games, rows.Err()
+}
+
+func scanGame(row pgx.Row) (quizzer.Game, error) {
+	var game quizzer.Game
+	var settings []byte
+	if err := row.Scan(&game.ID, &game.QuizID, &game.HostID, &game.Mode, &settings, &game.OpensAt, &game.ClosesAt); err != nil {
+		return quizzer.Game{}, err
+	}
+	game.Settings = settings
+	return game, nil
+}
+
+func (s *session) CreateGameParticipant(ctx context.Context, participant quizzer.GameParticipant) error {
+	log.Debug().Str("id", participant.ID).Str("gameID", participant.GameID).Str("userID", participant.UserID).Msg("creating game participant")
+
+	sql, args, err := psql().Insert("game_participants").
+		Columns("id", "game_id", "user_id", "name", "avatar", "color", "is_guest", "score", "started_at", "finished_at").
+		Values(
+			participant.ID,
+			participant.GameID,
+			participant.UserID,
+			participant.Name,
+			participant.Avatar,
+			participant.Color,
+			participant.IsGuest,
+			participant.Score,
+			participant.StartedAt,
+			participant.FinishedAt).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = s.conn.Exec(ctx, sql, args...)
+	return err
+}
+
+func (s *session) GetGameParticipant(ctx context.Context, gameID, userID string) (quizzer.GameParticipant, error) {
+	log.Debug().Str("gameID", gameID).Str("userID", userID).Msg("getting game participant")
+
+	sql, args, err := psql().Select("id", "game_id", "user_id", "name", "avatar", "color", "is_guest", "score", "started_at", "finished_at").
+		From("game_participants").
+		Where(sq.Eq{"game_id": gameID, "user_id": userID}).ToSql()
+	if err != nil {
+		return quizzer.GameParticipant{}, err
+	}
+
+	var p quizzer.GameParticipant
+	err = s.conn.QueryRow(ctx, sql, args...).
+		Scan(&p.ID, &p.GameID, &p.UserID, &p.Name, &p.Avatar, &p.Color, &p.IsGuest, &p.Score, &p.StartedAt, &p.FinishedAt)
+	return p, err
+}
+
+// ListGameParticipants returns the participants of the game, best score
+// first.
+func (s *session) ListGameParticipants(ctx context.Context, gameID string) ([]quizzer.GameParticipant, error) {
+	log.Debug().Str("gameID", gameID).Msg("listing game participants")
+
+	sql, args, err := psql().Select("id", "game_id", "user_id", "name", "avatar", "color", "is_guest", "score", "started_at", "finished_at").
+		From("game_participants").
+		Where(sq.Eq{"game_id": gameID}).
+		OrderBy("score DESC", "started_at").ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.conn.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	participants := []quizzer.GameParticipant{}
+	for rows.Next() {
+		var p quizzer.GameParticipant
+		if err := rows.Scan(&p.ID, &p.GameID, &p.UserID, &p.Name, &p.Avatar, &p.Color, &p.IsGuest, &p.Score, &p.StartedAt, &p.FinishedAt); err != nil {
+			return nil, err
+		}
+		participants = append(participants, p)
+	}
+
+	return participants, rows.Err()
+}
+
+// UpdateGameParticipant saves the score of the participant and when they
+// finished.
+func (s *session) UpdateGameParticipant(ctx context.Context, participant quizzer.GameParticipant) error {
+	log.Debug().Str("id", participant.ID).Int("score", participant.Score).Msg("updating game participant")
+
+	sql, args, err := psql().Update("game_participants").
+		Set("score", participant.Score).
+		Set("finished_at", participant.FinishedAt).
+		Where(sq.Eq{"id": participant.ID}).ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = s.conn.Exec(ctx, sql, args...)
+	return err
+}
+
+// SaveGameAnswer creates or replaces the answer of the participant to the
+// question.
+func (s *session) SaveGameAnswer(ctx context.Context, answer quizzer.GameAnswer) error {
+	log.Debug().Str("participantID", answer.ParticipantID).Str("questionID", answer.QuestionID).Msg("saving game answer")
+
+	sql, args, err := psql().Insert("game_answers").
+		Columns("participant_id", "question_id", "option_id", "is_correct", "score", "shown_at", "answered_at").
+		Values(
+			answer.ParticipantID,
+			answer.QuestionID,
+			answer.OptionID,
+			answer.IsCorrect,
+			answer.Score,
+			answer.ShownAt,
+			answer.AnsweredAt).
+		Suffix(`ON CONFLICT (participant_id, question_id) DO UPDATE SET option_id = EXCLUDED.option_id, is_correct = EXCLUDED.is_correct, score = EXCLUDED.score, shown_at = EXCLUDED.shown_at, answered_at = EXCLUDED.answered_at`).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = s.conn.Exec(ctx, sql, args...)
+	return err
+}
+
+func (s *session) ListGameAnswers(ctx context.Context, participantIDs ...string) ([]quizzer.GameAnswer, error) {
+	log.Debug().Strs("participantIDs", participantIDs).Msg("listing game answers")
+
+	sql, args, err := psql().Select("participant_id", "question_id", "option_id", "is_correct", "score", "shown_at", "answered_at").
+		From("game_answers").
+		Where(sq.Eq{"participant_id": participantIDs}).
+		OrderBy("participant_id", "shown_at", "answered_at").ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.conn.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	answers := []quizzer.GameAnswer{}
+	for rows.Next() {
+		var a quizzer.GameAnswer
+		if err := rows.Scan(&a.ParticipantID, &a.QuestionID, &a.OptionID, &a.IsCorrect, &a.Score, &a.ShownAt, &a.AnsweredAt); err != nil {
+			return nil, err
+		}
+		answers = append(answers, a)
+	}
+
+	return answers, rows.Err()
+}
@@ -0,0 +1,182 @@
+package postgres_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/william-joh/quizzer/server/internal/quizzer"
+)
+
+func TestGames(t *testing.T) {
+	db := SetupTestDB(t)
+
+	err := db.Do(context.Background()).CreateUser(context.Background(), "testuser-id", "testuser", "testpassword")
+	require.NoError(t, err)
+
+	err = db.Do(context.Background()).CreateQuiz(context.Background(), "testquiz-id", "testquiz", "testuser-id")
+	require.NoError(t, err)
+
+	err = db.Do(context.Background()).CreateQuestion(context.Background(), quizzer.Question{
+		ID:               "testquestion-id",
+		QuizID:           "testquiz-id",
+		Question:         "testquestion",
+		Index:            1,
+		TimeLimitSeconds: 10,
+		Options:          answerOptions("testquestion-id", 0, 2),
+	})
+	require.NoError(t, err)
+
+	opensAt := time.Now().Truncate(time.Microsecond)
+	game := quizzer.Game{
+		ID:       "testgame-id",
+		QuizID:   "testquiz-id",
+		HostID:   "testuser-id",
+		Mode:     quizzer.GameModeAssignment,
+		Settings: json.RawMessage(`{"scoringMode": "correct"}`),
+		OpensAt:  opensAt,
+		ClosesAt: opensAt.Add(time.Hour),
+	}
+
+	t.Run("get non-existing game", func(t *testing.T) {
+		_, err := db.Do(context.Background()).GetGame(context.Background(), "testgame")
+		require.Error(t, err)
+	})
+
+	t.Run("create game", func(t *testing.T) {
+		err := db.Do(context.Background()).CreateGame(context.Background(), game)
+		require.NoError(t, err)
+
+		err = db.Do(context.Background()).CreateGame(context.Background(), quizzer.Game{
+			ID:       "testgame-id2",
+			QuizID:   "testquiz-id",
+			HostID:   "testuser-id",
+			Mode:     quizzer.GameModeLive,
+			OpensAt:  opensAt.Add(-time.Hour),
+			ClosesAt: opensAt.Add(-time.Minute),
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("create game that closes before it opens", func(t *testing.T) {
+		err := db.Do(context.Background()).CreateGame(context.Background(), quizzer.Game{
+			ID:       "testgame-id3",
+			QuizID:   "testquiz-id",
+			HostID:   "testuser-id",
+			Mode:     quizzer.GameModeAssignment,
+			OpensAt:  opensAt,
+			ClosesAt: opensAt.Add(-time.Hour),
+		})
+		require.Error(t, err)
+	})
+
+	t.Run("get game", func(t *testing.T) {
+		g, err := db.Do(context.Background()).GetGame(context.Background(), game.ID)
+		require.NoError(t, err)
+		require.Equal(t, game.ID, g.ID)
+		require.Equal(t, game.Mode, g.Mode)
+		require.JSONEq(t, string(game.Settings), string(g.Settings))
+		require.True(t, game.OpensAt.Equal(g.OpensAt))
+		require.True(t, game.ClosesAt.Equal(g.ClosesAt))
+	})
+
+	t.Run("list games", func(t *testing.T) {
+		games, err := db.Do(context.Background()).ListGames(context.Background(), "testuser-id")
+		require.NoError(t, err)
+		require.Len(t, games, 2)
+		require.Equal(t, "testgame-id", games[0].ID)
+		require.Equal(t, "testgame-id2", games[1].ID)
+		require.JSONEq(t, `{}`, string(games[1].Settings))
+	})
+
+	participant := quizzer.GameParticipant{
+		ID:        "testparticipant-id",
+		GameID:    game.ID,
+		UserID:    "testguest-id",
+		Name:      "testguest",
+		Avatar:    "fox",
+		Color:     "#e53935",
+		IsGuest:   true,
+		StartedAt: opensAt,
+	}
+
+	t.Run("create game participant", func(t *testing.T) {
+		err := db.Do(context.Background()).CreateGameParticipant(context.Background(), participant)
+		require.NoError(t, err)
+
+		err = db.Do(context.Background()).CreateGameParticipant(context.Background(), quizzer.GameParticipant{
+			ID:        "testparticipant-id2",
+			GameID:    game.ID,
+			UserID:    "testuser-id",
+			Name:      "testuser",
+			Score:     1,
+			StartedAt: opensAt,
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("create duplicate game participant", func(t *testing.T) {
+		participant := participant
+		participant.ID = "testparticipant-id3"
+		err := db.Do(context.Background()).CreateGameParticipant(context.Background(), participant)
+		require.Error(t, err)
+	})
+
+	t.Run("update game participant", func(t *testing.T) {
+		finishedAt := opensAt.Add(time.Minute)
+		participant.Score = 2
+		participant.FinishedAt = &finishedAt
+		err := db.Do(context.Background()).UpdateGameParticipant(context.Background(), participant)
+		require.NoError(t, err)
+
+		p, err := db.Do(context.Background()).GetGameParticipant(context.Background(), game.ID, "testguest-id")
+		require.NoError(t, err)
+		require.Equal(t, participant.ID, p.ID)
+		require.Equal(t, 2, p.Score)
+		require.Equal(t, "fox", p.Avatar)
+		require.Equal(t, "#e53935", p.Color)
+		require.True(t, p.IsGuest)
+		require.NotNil(t, p.FinishedAt)
+		require.True(t, finishedAt.Equal(*p.FinishedAt))
+	})
+
+	t.Run("list game participants", func(t *testing.T) {
+		participants, err := db.Do(context.Background()).ListGameParticipants(context.Background(), game.ID)
+		require.NoError(t, err)
+		require.Len(t, participants, 2)
+		require.Equal(t, "testparticipant-id", participants[0].ID)
+		require.Equal(t, "testparticipant-id2", participants[1].ID)
+		require.Equal(t, "fox", participants[0].Avatar)
+		require.Empty(t, participants[1].Avatar)
+	})
+
+	t.Run("save game answer", func(t *testing.T) {
+		shownAt := opensAt.Add(time.Second)
+		answer := quizzer.GameAnswer{
+			ParticipantID: participant.ID,
+			QuestionID:    "testquestion-id",
+			ShownAt:       &shownAt,
+		}
+		err := db.Do(context.Background()).SaveGameAnswer(context.Background(), answer)
+		require.NoError(t, err)
+
+		answeredAt := shownAt.Add(time.Second)
+		answer.OptionID = asPtr("testquestion-id-option1")
+		answer.IsCorrect = true
+		answer.Score = 1
+		answer.AnsweredAt = &answeredAt
+		err = db.Do(context.Background()).SaveGameAnswer(context.Background(), answer)
+		require.NoError(t, err)
+
+		answers, err := db.Do(context.Background()).ListGameAnswers(context.Background(), participant.ID)
+		require.NoError(t, err)
+		require.Len(t, answers, 1)
+		require.Equal(t, "testquestion-id-option1", *answers[0].OptionID)
+		require.True(t, answers[0].IsCorrect)
+		require.Equal(t, 1, answers[0].Score)
+		require.True(t, shownAt.Equal(*answers[0].ShownAt))
+		require.True(t, answeredAt.Equal(*answers[0].AnsweredAt))
+	})
+}
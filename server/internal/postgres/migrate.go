@@ -57,6 +57,165 @@ CREATE TABLE questions (
 	`,
 		`-- noop`)
 
+	m.AppendMigration("answer options",
+		`
+CREATE TABLE answer_options (
+	id TEXT PRIMARY KEY,
+	question_id TEXT NOT NULL,
+	index INT NOT NULL,
+	text TEXT NOT NULL,
+	image_url TEXT,
+	is_correct BOOLEAN NOT NULL DEFAULT FALSE,
+	CONSTRAINT fk_question FOREIGN KEY(question_id) REFERENCES questions(id) ON DELETE CASCADE,
+	CONSTRAINT unique_question_index UNIQUE (question_id, index)
+);
+
+INSERT INTO answer_options (id, question_id, index, text, is_correct)
+SELECT gen_random_uuid()::TEXT, q.id, a.ordinality - 1, a.answer, a.answer = ANY(q.correct_answers)
+FROM questions q, unnest(q.answers) WITH ORDINALITY AS a(answer, ordinality);
+
+ALTER TABLE questions DROP COLUMN answers, DROP COLUMN correct_answers;
+	`,
+		`
+ALTER TABLE questions ADD COLUMN answers TEXT[], ADD COLUMN correct_answers TEXT[];
+
+UPDATE questions q SET
+	answers = ARRAY(SELECT text FROM answer_options WHERE question_id = q.id ORDER BY index),
+	correct_answers = ARRAY(SELECT text FROM answer_options WHERE question_id = q.id AND is_correct ORDER BY index);
+
+ALTER TABLE questions ALTER COLUMN answers SET NOT NULL, ALTER COLUMN correct_answers SET NOT NULL;
+
+DROP TABLE answer_options;
+	`)
+
+	m.AppendMigration("game history",
+		`
+CREATE TABLE games (
+	id TEXT PRIMARY KEY,
+	quiz_id TEXT NOT NULL,
+	host_id TEXT NOT NULL,
+	mode TEXT NOT NULL,
+	settings JSONB NOT NULL DEFAULT '{}',
+	opens_at TIMESTAMPTZ NOT NULL,
+	closes_at TIMESTAMPTZ NOT NULL CHECK (closes_at > opens_at),
+	CONSTRAINT fk_quiz FOREIGN KEY(quiz_id) REFERENCES quizzes(id) ON DELETE CASCADE,
+	CONSTRAINT fk_user FOREIGN KEY(host_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX games_host_id_idx ON games (host_id);
+
+CREATE TABLE game_participants (
+	id TEXT PRIMARY KEY,
+	game_id TEXT NOT NULL,
+	user_id TEXT NOT NULL,
+	name TEXT NOT NULL,
+	is_guest BOOLEAN NOT NULL DEFAULT FALSE,
+	score INT NOT NULL DEFAULT 0,
+	started_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	finished_at TIMESTAMPTZ,
+	CONSTRAINT fk_game FOREIGN KEY(game_id) REFERENCES games(id) ON DELETE CASCADE,
+	CONSTRAINT unique_game_user UNIQUE (game_id, user_id)
+);
+
+CREATE TABLE game_answers (
+	participant_id TEXT NOT NULL,
+	question_id TEXT NOT NULL,
+	option_id TEXT,
+	is_correct BOOLEAN NOT NULL DEFAULT FALSE,
+	score INT NOT NULL DEFAULT 0,
+	shown_at TIMESTAMPTZ,
+	answered_at TIMESTAMPTZ,
+	PRIMARY KEY (participant_id, question_id),
+	CONSTRAINT fk_participant FOREIGN KEY(participant_id) REFERENCES game_participants(id) ON DELETE CASCADE,
+	CONSTRAINT fk_question FOREIGN KEY(question_id) REFERENCES questions(id) ON DELETE CASCADE,
+	CONSTRAINT fk_option FOREIGN KEY(option_id) REFERENCES answer_options(id) ON DELETE SET NULL
+);
+	`,
+		`
+DROP TABLE game_answers;
+DROP TABLE game_participants;
+DROP TABLE games;
+	`)
+
+	m.AppendMigration("scheduled games",
+		`
+CREATE TABLE scheduled_games (
+	id TEXT PRIMARY KEY,
+	quiz_id TEXT NOT NULL,
+	host_id TEXT NOT NULL,
+	start_at TIMESTAMPTZ NOT NULL,
+	settings JSONB NOT NULL DEFAULT '{}',
+	status TEXT NOT NULL DEFAULT 'pending',
+	code TEXT,
+	CONSTRAINT fk_quiz FOREIGN KEY(quiz_id) REFERENCES quizzes(id) ON DELETE CASCADE,
+	CONSTRAINT fk_user FOREIGN KEY(host_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX scheduled_games_status_start_at_idx ON scheduled_games (status, start_at);
+	`,
+		`
+DROP TABLE scheduled_games;
+	`)
+
+	m.AppendMigration("participant avatars",
+		`
+ALTER TABLE game_participants ADD COLUMN avatar TEXT NOT NULL DEFAULT '', ADD COLUMN color TEXT NOT NULL DEFAULT '';
+	`,
+		`
+ALTER TABLE game_participants DROP COLUMN avatar, DROP COLUMN color;
+	`)
+
+	m.AppendMigration("slides",
+		`
+CREATE TABLE slides (
+	id TEXT PRIMARY KEY,
+	quiz_id TEXT NOT NULL,
+	index INT NOT NULL,
+	title TEXT NOT NULL,
+	body TEXT NOT NULL DEFAULT '',
+	image_url TEXT,
+	video_url TEXT,
+	CONSTRAINT fk_quiz FOREIGN KEY(quiz_id) REFERENCES quizzes(id) ON DELETE CASCADE
+);
+	`,
+		`
+DROP TABLE slides;
+	`)
+
+	m.AppendMigration("sections",
+		`
+CREATE TABLE sections (
+	id TEXT PRIMARY KEY,
+	quiz_id TEXT NOT NULL,
+	index INT NOT NULL,
+	title TEXT NOT NULL,
+	multiplier INT NOT NULL DEFAULT 0,
+	CONSTRAINT fk_quiz FOREIGN KEY(quiz_id) REFERENCES quizzes(id) ON DELETE CASCADE
+);
+
+ALTER TABLE questions ADD COLUMN section_id TEXT REFERENCES sections(id) ON DELETE SET NULL;
+	`,
+		`
+ALTER TABLE questions DROP COLUMN section_id;
+DROP TABLE sections;
+	`)
+
+	m.AppendMigration("question media",
+		`
+ALTER TABLE questions ADD COLUMN image_url TEXT, ADD COLUMN audio_url TEXT;
+	`,
+		`
+ALTER TABLE questions DROP COLUMN image_url, DROP COLUMN audio_url;
+	`)
+
+	m.AppendMigration("answer option audio",
+		`
+ALTER TABLE answer_options ADD COLUMN audio_url TEXT;
+	`,
+		`
+ALTER TABLE answer_options DROP COLUMN audio_url;
+	`)
+
 	if err := m.Migrate(ctx); err != nil {
 		return fmt.Errorf("migrate: %w", err)
 	}
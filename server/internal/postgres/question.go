@@ -9,33 +9,37 @@ import (
 )
 
 func (s *session) CreateQuestion(ctx context.Context, question quizzer.Question) error {
-log.Debug().Str("id", question.ID).Str("quizID", question.QuizID).Str("question", question.Question).Int("index", question.Index).Int("timeLimit", int(question.TimeLimitSeconds)).Strs("answers", question.Answers).Strs("correctAnswers", question.CorrectAnswers).Msg("creating question")
+	log.Debug().Str("id", question.ID).Str("quizID", question.QuizID).Str("question", question.Question).Int("index", question.Index).Int("timeLimit", int(question.TimeLimitSeconds)).Int("nrOptions", len(question.Options)).Msg("creating question")
 
 	sql, args, err := psql().Insert("questions").
-		Columns("id", "quiz_id", "question", "index", "time_limit_seconds", "answers", "correct_answers", "video_url", "video_start_time_seconds", "video_end_time_seconds").
+		Columns("id", "quiz_id", "section_id", "question", "index", "time_limit_seconds", "video_url", "video_start_time_seconds", "video_end_time_seconds", "image_url", "audio_url").
 		Values(
 			question.ID, question.QuizID,
+			question.SectionID,
 			question.Question,
 			question.Index,
 			question.TimeLimitSeconds,
-			question.Answers,
-			question.CorrectAnswers,
 			question.VideoURL,
 			question.VideoStartTimeSeconds,
-			question.VideoEndTimeSeconds).
+			question.VideoEndTimeSeconds,
+			question.ImageURL,
+			question.AudioURL).
 		ToSql()
 	if err != nil {
 		return err
 	}
 
-	_, err = s.conn.Exec(ctx, sql, args...)
-	return err
+	if _, err := s.conn.Exec(ctx, sql, args...); err != nil {
+		return err
+	}
+
+	return s.saveAnswerOptions(ctx, question.ID, question.Options)
 }
 
 func (s *session) GetQuestion(ctx context.Context, id string) (quizzer.Question, error) {
 	log.Debug().Str("id", id).Msg("getting question")
 
-	sql, args, err := psql().Select("id", "quiz_id", "question", "index", "time_limit_seconds", "answers", "correct_answers", "video_url", "video_start_time_seconds", "video_end_time_seconds").
+	sql, args, err := psql().Select("id", "quiz_id", "section_id", "question", "index", "time_limit_seconds", "video_url", "video_start_time_seconds", "video_end_time_seconds", "image_url", "audio_url").
 		From("questions").
 		Where(sq.Eq{"id": id}).ToSql()
 	if err != nil {
@@ -44,16 +48,27 @@ func (s *session) GetQuestion(ctx context.Context, id string) (quizzer.Question,
 
 	row := s.conn.QueryRow(ctx, sql, args...)
 	var question quizzer.Question
-	err = row.Scan(&question.ID, &question.QuizID, &question.Question, &question.Index, &question.TimeLimitSeconds, &question.Answers, &question.CorrectAnswers, &question.VideoURL, &question.VideoStartTimeSeconds, &question.VideoEndTimeSeconds)
-	return question, err
+	err = row.Scan(&question.ID, &question.QuizID, &question.SectionID, &question.Question, &question.Index, &question.TimeLimitSeconds, &question.VideoURL, &question.VideoStartTimeSeconds, &question.VideoEndTimeSeconds, &question.ImageURL, &question.AudioURL)
+	if err != nil {
+		return quizzer.Question{}, err
+	}
+
+	options, err := s.listAnswerOptions(ctx, question.ID)
+	if err != nil {
+		return quizzer.Question{}, err
+	}
+	question.Options = options[question.ID]
+
+	return question, nil
 }
 
 func (s *session) ListQuestions(ctx context.Context, quizID string) ([]quizzer.Question, error) {
 	log.Debug().Str("quizID", quizID).Msg("listing questions")
 
-	sql, args, err := psql().Select("id", "quiz_id", "question", "index", "time_limit_seconds", "answers", "correct_answers", "video_url", "video_start_time_seconds", "video_end_time_seconds").
+	sql, args, err := psql().Select("id", "quiz_id", "section_id", "question", "index", "time_limit_seconds", "video_url", "video_start_time_seconds", "video_end_time_seconds", "image_url", "audio_url").
 		From("questions").
-		Where(sq.Eq{"quiz_id": quizID}).ToSql()
+		Where(sq.Eq{"quiz_id": quizID}).
+		OrderBy("index").ToSql()
 	if err != nil {
 		return nil, err
 	}
@@ -65,38 +80,57 @@ func (s *session) ListQuestions(ctx context.Context, quizID string) ([]quizzer.Q
 	defer rows.Close()
 
 	var questions []quizzer.Question
+	var ids []string
 	for rows.Next() {
 		var question quizzer.Question
-		err = rows.Scan(&question.ID, &question.QuizID, &question.Question, &question.Index, &question.TimeLimitSeconds, &question.Answers, &question.CorrectAnswers, &question.VideoURL, &question.VideoStartTimeSeconds, &question.VideoEndTimeSeconds)
+		err = rows.Scan(&question.ID, &question.QuizID, &question.SectionID, &question.Question, &question.Index, &question.TimeLimitSeconds, &question.VideoURL, &question.VideoStartTimeSeconds, &question.VideoEndTimeSeconds, &question.ImageURL, &question.AudioURL)
 		if err != nil {
 			return nil, err
 		}
 		questions = append(questions, question)
+		ids = append(ids, question.ID)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	options, err := s.listAnswerOptions(ctx, ids...)
+	if err != nil {
+		return nil, err
+	}
+	for i := range questions {
+		questions[i].Options = options[questions[i].ID]
+	}
+
 	return questions, nil
 }
 
 func (s *session) UpdateQuestion(ctx context.Context, question quizzer.Question) error {
-	log.Debug().Str("id", question.ID).Str("question", question.Question).Int("index", question.Index).Uint64("timeLimit", question.TimeLimitSeconds).Strs("answers", question.Answers).Strs("correctAnswers", question.CorrectAnswers).Msg("updating question")
+	log.Debug().Str("id", question.ID).Str("question", question.Question).Int("index", question.Index).Uint64("timeLimit", question.TimeLimitSeconds).Int("nrOptions", len(question.Options)).Msg("updating question")
 
 	sql, args, err := psql().Update("questions").
 		SetMap(map[string]interface{}{
+			"section_id":               question.SectionID,
 			"question":                 question.Question,
 			"index":                    question.Index,
 			"time_limit_seconds":       question.TimeLimitSeconds,
-			"answers":                  question.Answers,
-			"correct_answers":          question.CorrectAnswers,
 			"video_url":                question.VideoURL,
 			"video_start_time_seconds": question.VideoStartTimeSeconds,
 			"video_end_time_seconds":   question.VideoEndTimeSeconds,
+			"image_url":                question.ImageURL,
+			"audio_url":                question.AudioURL,
 		}).
 		Where(sq.Eq{"id": question.ID}).ToSql()
 	if err != nil {
 		return err
 	}
 
-	_, err = s.conn.Exec(ctx, sql, args...)
-	return err
+	if _, err := s.conn.Exec(ctx, sql, args...); err != nil {
+		return err
+	}
+
+	return s.saveAnswerOptions(ctx, question.ID, question.Options)
 }
 
 func (s *session) DeleteQuestion(ctx context.Context, id string) error {
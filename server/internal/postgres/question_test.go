@@ -2,6 +2,7 @@ package postgres_test
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -22,6 +23,10 @@ func TestQuestions(t *testing.T) {
 		require.Error(t, err)
 	})
 
+	mediaOptions := answerOptions("testquestion-id2", 1, 3)
+	mediaOptions[0].ImageURL = asPtr("/media/testoption.png")
+	mediaOptions[1].AudioURL = asPtr("/media/testoption.mp3")
+
 	t.Run("create question", func(t *testing.T) {
 		err := db.Do(context.Background()).CreateQuestion(context.Background(), quizzer.Question{
 			ID:                    "testquestion-id1",
@@ -29,8 +34,7 @@ func TestQuestions(t *testing.T) {
 			Question:              "testquestion1",
 			Index:                 1,
 			TimeLimitSeconds:      10,
-			Answers:               []string{"answer1", "answer2", "answer3"},
-			CorrectAnswers:        []string{"answer1"},
+			Options:               answerOptions("testquestion-id1", 0, 3),
 			VideoURL:              asPtr("testurl"),
 			VideoStartTimeSeconds: asPtr(uint64(10)),
 			VideoEndTimeSeconds:   asPtr(uint64(20)),
@@ -43,8 +47,9 @@ func TestQuestions(t *testing.T) {
 			Question:         "testquestion2",
 			Index:            2,
 			TimeLimitSeconds: 20,
-			Answers:          []string{"answer1", "answer2", "answer3"},
-			CorrectAnswers:   []string{"answer2"},
+			Options:          mediaOptions,
+			ImageURL:         asPtr("/media/testimage.png"),
+			AudioURL:         asPtr("/media/testaudio.mp3"),
 		})
 		require.NoError(t, err)
 
@@ -54,8 +59,7 @@ func TestQuestions(t *testing.T) {
 			Question:         "testquestion3",
 			Index:            3,
 			TimeLimitSeconds: 30,
-			Answers:          []string{"answer1", "answer2", "answer3"},
-			CorrectAnswers:   []string{"answer3"},
+			Options:          answerOptions("testquestion-id3", 2, 3),
 		})
 		require.NoError(t, err)
 	})
@@ -71,8 +75,7 @@ func TestQuestions(t *testing.T) {
 			Question:              "testquestion1",
 			Index:                 1,
 			TimeLimitSeconds:      10,
-			Answers:               []string{"answer1", "answer2", "answer3"},
-			CorrectAnswers:        []string{"answer1"},
+			Options:               answerOptions("testquestion-id1", 0, 3),
 			VideoURL:              asPtr("testurl"),
 			VideoStartTimeSeconds: asPtr(uint64(10)),
 			VideoEndTimeSeconds:   asPtr(uint64(20)),
@@ -85,9 +88,10 @@ func TestQuestions(t *testing.T) {
 			Question:         "testquestion2",
 			Index:            2,
 			TimeLimitSeconds: 20,
-			Answers:          []string{"answer1", "answer2", "answer3"},
-			CorrectAnswers:   []string{"answer2"},
+			Options:          mediaOptions,
 			VideoURL:         nil,
+			ImageURL:         asPtr("/media/testimage.png"),
+			AudioURL:         asPtr("/media/testaudio.mp3"),
 		}
 		require.Equal(t, expectedQuestion2, questions[1])
 	})
@@ -101,8 +105,7 @@ func TestQuestions(t *testing.T) {
 			Question:              "testquestion1",
 			Index:                 1,
 			TimeLimitSeconds:      10,
-			Answers:               []string{"answer1", "answer2", "answer3"},
-			CorrectAnswers:        []string{"answer1"},
+			Options:               answerOptions("testquestion-id1", 0, 3),
 			VideoURL:              asPtr("testurl"),
 			VideoStartTimeSeconds: asPtr(uint64(10)),
 			VideoEndTimeSeconds:   asPtr(uint64(20)),
@@ -117,8 +120,7 @@ func TestQuestions(t *testing.T) {
 			Question:              "editedquestion1",
 			Index:                 1,
 			TimeLimitSeconds:      10,
-			Answers:               []string{"answer1", "answer2", "answer3", "answer4"},
-			CorrectAnswers:        []string{"answer3"},
+			Options:               answerOptions("testquestion-id1", 2, 4),
 			VideoURL:              asPtr("editedurl"),
 			VideoStartTimeSeconds: asPtr(uint64(20)),
 			VideoEndTimeSeconds:   asPtr(uint64(300)),
@@ -133,8 +135,7 @@ func TestQuestions(t *testing.T) {
 			Question:              "editedquestion1",
 			Index:                 1,
 			TimeLimitSeconds:      10,
-			Answers:               []string{"answer1", "answer2", "answer3", "answer4"},
-			CorrectAnswers:        []string{"answer3"},
+			Options:               answerOptions("testquestion-id1", 2, 4),
 			VideoURL:              asPtr("editedurl"),
 			VideoStartTimeSeconds: asPtr(uint64(20)),
 			VideoEndTimeSeconds:   asPtr(uint64(300)),
@@ -155,6 +156,20 @@ func TestQuestions(t *testing.T) {
 	})
 }
 
+func answerOptions(questionID string, correct, n int) []quizzer.AnswerOption {
+	options := []quizzer.AnswerOption{}
+	for i := range n {
+		options = append(options, quizzer.AnswerOption{
+			ID:         fmt.Sprintf("%s-option%d", questionID, i+1),
+			QuestionID: questionID,
+			Index:      i,
+			Text:       fmt.Sprintf("answer%d", i+1),
+			IsCorrect:  i == correct,
+		})
+	}
+	return options
+}
+
 func asPtr[T any](s T) *T {
 	return &s
 }
@@ -0,0 +1,157 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog/log"
+	"github.com/william-joh/quizzer/server/internal/quizzer"
+)
+
+func (s *session) CreateScheduledGame(ctx context.Context, game quizzer.ScheduledGame) error {
+	log.Debug().Str("id", game.ID).Str("quizID", game.QuizID).Time("startAt", game.StartAt).Msg("creating scheduled game")
+
+	settings := string(game.Settings)
+	if settings == "" {
+		settings = "{}"
+	}
+
+	status := game.Status
+	if status == "" {
+		status = quizzer.ScheduledGameStatusPending
+	}
+
+	sql, args, err := psql().Insert("scheduled_games").
+		Columns("id", "quiz_id", "host_id", "start_at", "settings", "status", "code").
+		Values(game.ID, game.QuizID, game.HostID, game.StartAt, settings, status, game.Code).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = s.conn.Exec(ctx, sql, args...)
+	return err
+}
+
+func (s *session) GetScheduledGame(ctx context.Context, id string) (quizzer.ScheduledGame, error) {
+	log.Debug().Str("id", id).Msg("getting scheduled game")
+
+	sql, args, err := psql().Select("id", "quiz_id", "host_id", "start_at", "settings", "status", "code").
+		From("scheduled_games").
+		Where(sq.Eq{"id": id}).ToSql()
+	if err != nil {
+		return quizzer.ScheduledGame{}, err
+	}
+
+	return scanScheduledGame(s.conn.QueryRow(ctx, sql, args...))
+}
+
+// LockScheduledGame gets the scheduled game and locks it until the transaction
+// ends, so that it is not opened and changed at the same time.
+func (s *session) LockScheduledGame(ctx context.Context, id string) (quizzer.ScheduledGame, error) {
+	log.Debug().Str("id", id).Msg("locking scheduled game")
+
+	sql, args, err := psql().Select("id", "quiz_id", "host_id", "start_at", "settings", "status", "code").
+		From("scheduled_games").
+		Where(sq.Eq{"id": id}).
+		Suffix("FOR UPDATE").ToSql()
+	if err != nil {
+		return quizzer.ScheduledGame{}, err
+	}
+
+	return scanScheduledGame(s.conn.QueryRow(ctx, sql, args...))
+}
+
+// ListScheduledGames returns the games scheduled by the user, soonest first.
+func (s *session) ListScheduledGames(ctx context.Context, hostID string) ([]quizzer.ScheduledGame, error) {
+	log.Debug().Str("hostID", hostID).Msg("listing scheduled games")
+
+	return s.listScheduledGames(ctx, sq.Eq{"host_id": hostID})
+}
+
+// ListDueScheduledGames returns the pending games that should have opened at
+// the given time.
+func (s *session) ListDueScheduledGames(ctx context.Context, at time.Time) ([]quizzer.ScheduledGame, error) {
+	log.Trace().Time("at", at).Msg("listing due scheduled games")
+
+	return s.listScheduledGames(ctx, sq.And{
+		sq.Eq{"status": quizzer.ScheduledGameStatusPending},
+		sq.LtOrEq{"start_at": at},
+	})
+}
+
+func (s *session) listScheduledGames(ctx context.Context, where sq.Sqlizer) ([]quizzer.ScheduledGame, error) {
+	sql, args, err := psql().Select("id", "quiz_id", "host_id", "start_at", "settings", "status", "code").
+		From("scheduled_games").
+		Where(where).
+		OrderBy("start_at").ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.conn.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	games := []quizzer.ScheduledGame{}
+	for rows.Next() {
+		game, err := scanScheduledGame(rows)
+		if err != nil {
+			return nil, err
+		}
+		games = append(games, game)
+	}
+
+	return games, rows.Err()
+}
+
+func scanScheduledGame(row pgx.Row) (quizzer.ScheduledGame, error) {
+	var game quizzer.ScheduledGame
+	var settings []byte
+	if err := row.Scan(&game.ID, &game.QuizID, &game.HostID, &game.StartAt, &settings, &game.Status, &game.Code); err != nil {
+		return quizzer.ScheduledGame{}, err
+	}
+	game.Settings = settings
+	return game, nil
+}
+
+// UpdateScheduledGame saves the start time, settings, status and code of the
+// scheduled game.
+func (s *session) UpdateScheduledGame(ctx context.Context, game quizzer.ScheduledGame) error {
+	log.Debug().Str("id", game.ID).Time("startAt", game.StartAt).Str("status", string(game.Status)).Msg("updating scheduled game")
+
+	settings := string(game.Settings)
+	if settings == "" {
+		settings = "{}"
+	}
+
+	sql, args, err := psql().Update("scheduled_games").
+		Set("start_at", game.StartAt).
+		Set("settings", settings).
+		Set("status", game.Status).
+		Set("code", game.Code).
+		Where(sq.Eq{"id": game.ID}).ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = s.conn.Exec(ctx, sql, args...)
+	return err
+}
+
+func (s *session) DeleteScheduledGame(ctx context.Context, id string) error {
+	log.Debug().Str("id", id).Msg("deleting scheduled game")
+
+	sql, args, err := psql().Delete("scheduled_games").
+		Where(sq.Eq{"id": id}).ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = s.conn.Exec(ctx, sql, args...)
+	return err
+}
@@ -0,0 +1,117 @@
+package postgres_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+	"github.com/william-joh/quizzer/server/internal/postgres"
+	"github.com/william-joh/quizzer/server/internal/quizzer"
+)
+
+func TestScheduledGames(t *testing.T) {
+	db := SetupTestDB(t)
+
+	err := db.Do(context.Background()).CreateUser(context.Background(), "testuser-id", "testuser", "testpassword")
+	require.NoError(t, err)
+
+	err = db.Do(context.Background()).CreateQuiz(context.Background(), "testquiz-id", "testquiz", "testuser-id")
+	require.NoError(t, err)
+
+	now := time.Now().Truncate(time.Microsecond)
+
+	t.Run("get non-existing scheduled game", func(t *testing.T) {
+		_, err := db.Do(context.Background()).GetScheduledGame(context.Background(), "testscheduled")
+		require.Error(t, err)
+	})
+
+	t.Run("create scheduled game", func(t *testing.T) {
+		err := db.Do(context.Background()).CreateScheduledGame(context.Background(), quizzer.ScheduledGame{
+			ID:       "testscheduled-id1",
+			QuizID:   "testquiz-id",
+			HostID:   "testuser-id",
+			StartAt:  now.Add(time.Hour),
+			Settings: json.RawMessage(`{"scoringMode": "speed"}`),
+		})
+		require.NoError(t, err)
+
+		err = db.Do(context.Background()).CreateScheduledGame(context.Background(), quizzer.ScheduledGame{
+			ID:      "testscheduled-id2",
+			QuizID:  "testquiz-id",
+			HostID:  "testuser-id",
+			StartAt: now.Add(-time.Minute),
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("get scheduled game", func(t *testing.T) {
+		game, err := db.Do(context.Background()).GetScheduledGame(context.Background(), "testscheduled-id1")
+		require.NoError(t, err)
+		require.Equal(t, "testquiz-id", game.QuizID)
+		require.Equal(t, "testuser-id", game.HostID)
+		require.True(t, now.Add(time.Hour).Equal(game.StartAt))
+		require.JSONEq(t, `{"scoringMode": "speed"}`, string(game.Settings))
+		require.Equal(t, quizzer.ScheduledGameStatusPending, game.Status)
+		require.Nil(t, game.Code)
+	})
+
+	t.Run("lock scheduled game", func(t *testing.T) {
+		err := db.InTx(context.Background(), func(s postgres.Session) error {
+			game, err := s.LockScheduledGame(context.Background(), "testscheduled-id1")
+			require.NoError(t, err)
+			require.Equal(t, "testquiz-id", game.QuizID)
+			require.Equal(t, quizzer.ScheduledGameStatusPending, game.Status)
+
+			_, err = s.LockScheduledGame(context.Background(), "testscheduled")
+			require.ErrorIs(t, err, pgx.ErrNoRows)
+			return nil
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("list scheduled games", func(t *testing.T) {
+		games, err := db.Do(context.Background()).ListScheduledGames(context.Background(), "testuser-id")
+		require.NoError(t, err)
+		require.Len(t, games, 2)
+		require.Equal(t, "testscheduled-id2", games[0].ID)
+		require.Equal(t, "testscheduled-id1", games[1].ID)
+	})
+
+	t.Run("list due scheduled games", func(t *testing.T) {
+		games, err := db.Do(context.Background()).ListDueScheduledGames(context.Background(), now)
+		require.NoError(t, err)
+		require.Len(t, games, 1)
+		require.Equal(t, "testscheduled-id2", games[0].ID)
+	})
+
+	t.Run("update scheduled game", func(t *testing.T) {
+		game, err := db.Do(context.Background()).GetScheduledGame(context.Background(), "testscheduled-id2")
+		require.NoError(t, err)
+
+		game.Status = quizzer.ScheduledGameStatusOpened
+		game.Code = asPtr("123456")
+		err = db.Do(context.Background()).UpdateScheduledGame(context.Background(), game)
+		require.NoError(t, err)
+
+		games, err := db.Do(context.Background()).ListDueScheduledGames(context.Background(), now)
+		require.NoError(t, err)
+		require.Empty(t, games)
+
+		game, err = db.Do(context.Background()).GetScheduledGame(context.Background(), "testscheduled-id2")
+		require.NoError(t, err)
+		require.Equal(t, quizzer.ScheduledGameStatusOpened, game.Status)
+		require.Equal(t, "123456", *game.Code)
+	})
+
+	t.Run("delete scheduled game", func(t *testing.T) {
+		err := db.Do(context.Background()).DeleteScheduledGame(context.Background(), "testscheduled-id1")
+		require.NoError(t, err)
+
+		games, err := db.Do(context.Background()).ListScheduledGames(context.Background(), "testuser-id")
+		require.NoError(t, err)
+		require.Len(t, games, 1)
+	})
+}
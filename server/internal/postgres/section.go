@@ -0,0 +1,105 @@
+package postgres
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/rs/zerolog/log"
+	"github.com/william-joh/quizzer/server/internal/quizzer"
+)
+
+func (s *session) CreateSection(ctx context.Context, section quizzer.Section) error {
+	log.Debug().Str("id", section.ID).Str("quizID", section.QuizID).Str("title", section.Title).Int("index", section.Index).Msg("creating section")
+
+	sql, args, err := psql().Insert("sections").
+		Columns("id", "quiz_id", "index", "title", "multiplier").
+		Values(section.ID, section.QuizID, section.Index, section.Title, section.Multiplier).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = s.conn.Exec(ctx, sql, args...)
+	return err
+}
+
+func (s *session) GetSection(ctx context.Context, id string) (quizzer.Section, error) {
+	log.Debug().Str("id", id).Msg("getting section")
+
+	sql, args, err := psql().Select("id", "quiz_id", "index", "title", "multiplier").
+		From("sections").
+		Where(sq.Eq{"id": id}).ToSql()
+	if err != nil {
+		return quizzer.Section{}, err
+	}
+
+	row := s.conn.QueryRow(ctx, sql, args...)
+	var section quizzer.Section
+	err = row.Scan(&section.ID, &section.QuizID, &section.Index, &section.Title, &section.Multiplier)
+	if err != nil {
+		return quizzer.Section{}, err
+	}
+
+	return section, nil
+}
+
+func (s *session) ListSections(ctx context.Context, quizID string) ([]quizzer.Section, error) {
+	log.Debug().Str("quizID", quizID).Msg("listing sections")
+
+	sql, args, err := psql().Select("id", "quiz_id", "index", "title", "multiplier").
+		From("sections").
+		Where(sq.Eq{"quiz_id": quizID}).
+		OrderBy("index").ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.conn.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sections := []quizzer.Section{}
+	for rows.Next() {
+		var section quizzer.Section
+		err = rows.Scan(&section.ID, &section.QuizID, &section.Index, &section.Title, &section.Multiplier)
+		if err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+
+	return sections, rows.Err()
+}
+
+func (s *session) UpdateSection(ctx context.Context, section quizzer.Section) error {
+	log.Debug().Str("id", section.ID).Str("title", section.Title).Int("index", section.Index).Msg("updating section")
+
+	sql, args, err := psql().Update("sections").
+		SetMap(map[string]interface{}{
+			"index":      section.Index,
+			"title":      section.Title,
+			"multiplier": section.Multiplier,
+		}).
+		Where(sq.Eq{"id": section.ID}).ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = s.conn.Exec(ctx, sql, args...)
+	return err
+}
+
+func (s *session) DeleteSection(ctx context.Context, id string) error {
+	log.Debug().Str("id", id).Msg("deleting section")
+
+	sql, args, err := psql().Delete("sections").
+		Where(sq.Eq{"id": id}).ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = s.conn.Exec(ctx, sql, args...)
+	return err
+}
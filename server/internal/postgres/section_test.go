@@ -0,0 +1,116 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/william-joh/quizzer/server/internal/quizzer"
+)
+
+func TestSections(t *testing.T) {
+	db := SetupTestDB(t)
+
+	err := db.Do(context.Background()).CreateUser(context.Background(), "testuser-id", "testuser", "testpassword")
+	require.NoError(t, err)
+
+	err = db.Do(context.Background()).CreateQuiz(context.Background(), "testquiz-id", "testquiz", "testuser-id")
+	require.NoError(t, err)
+
+	t.Run("get non-existing section", func(t *testing.T) {
+		_, err := db.Do(context.Background()).GetSection(context.Background(), "testsection")
+		require.Error(t, err)
+	})
+
+	t.Run("create section", func(t *testing.T) {
+		err := db.Do(context.Background()).CreateSection(context.Background(), quizzer.Section{
+			ID:         "testsection-id2",
+			QuizID:     "testquiz-id",
+			Index:      1,
+			Title:      "testsection2",
+			Multiplier: 2,
+		})
+		require.NoError(t, err)
+
+		err = db.Do(context.Background()).CreateSection(context.Background(), quizzer.Section{
+			ID:     "testsection-id1",
+			QuizID: "testquiz-id",
+			Index:  0,
+			Title:  "testsection1",
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("list sections", func(t *testing.T) {
+		sections, err := db.Do(context.Background()).ListSections(context.Background(), "testquiz-id")
+		require.NoError(t, err)
+		require.Equal(t, []quizzer.Section{
+			{
+				ID:     "testsection-id1",
+				QuizID: "testquiz-id",
+				Index:  0,
+				Title:  "testsection1",
+			},
+			{
+				ID:         "testsection-id2",
+				QuizID:     "testquiz-id",
+				Index:      1,
+				Title:      "testsection2",
+				Multiplier: 2,
+			},
+		}, sections)
+	})
+
+	t.Run("question in section", func(t *testing.T) {
+		err := db.Do(context.Background()).CreateQuestion(context.Background(), quizzer.Question{
+			ID:               "testquestion-id",
+			QuizID:           "testquiz-id",
+			SectionID:        asPtr("testsection-id2"),
+			Question:         "testquestion",
+			Index:            0,
+			TimeLimitSeconds: 10,
+			Options:          answerOptions("testquestion-id", 0, 2),
+		})
+		require.NoError(t, err)
+
+		question, err := db.Do(context.Background()).GetQuestion(context.Background(), "testquestion-id")
+		require.NoError(t, err)
+		require.Equal(t, asPtr("testsection-id2"), question.SectionID)
+	})
+
+	t.Run("edit section", func(t *testing.T) {
+		err := db.Do(context.Background()).UpdateSection(context.Background(), quizzer.Section{
+			ID:         "testsection-id1",
+			QuizID:     "testquiz-id",
+			Index:      2,
+			Title:      "editedsection1",
+			Multiplier: 3,
+		})
+		require.NoError(t, err)
+
+		section, err := db.Do(context.Background()).GetSection(context.Background(), "testsection-id1")
+		require.NoError(t, err)
+		require.Equal(t, quizzer.Section{
+			ID:         "testsection-id1",
+			QuizID:     "testquiz-id",
+			Index:      2,
+			Title:      "editedsection1",
+			Multiplier: 3,
+		}, section)
+	})
+
+	t.Run("delete section", func(t *testing.T) {
+		err := db.Do(context.Background()).DeleteSection(context.Background(), "testsection-id2")
+		require.NoError(t, err)
+
+		sections, err := db.Do(context.Background()).ListSections(context.Background(), "testquiz-id")
+		require.NoError(t, err)
+		require.Len(t, sections, 1)
+		require.Equal(t, "testsection-id1", sections[0].ID)
+
+		// The questions of a deleted section are kept without a section.
+		question, err := db.Do(context.Background()).GetQuestion(context.Background(), "testquestion-id")
+		require.NoError(t, err)
+		require.Nil(t, question.SectionID)
+	})
+}
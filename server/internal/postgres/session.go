@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"context"
+	"time"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/jackc/pgx/v5"
@@ -71,6 +72,38 @@ type Session interface {
 	ListQuestions(ctx context.Context, quizID string) ([]quizzer.Question, error)
 	UpdateQuestion(ctx context.Context, question quizzer.Question) error
 	DeleteQuestion(ctx context.Context, id string) error
+
+	CreateSlide(ctx context.Context, slide quizzer.Slide) error
+	GetSlide(ctx context.Context, id string) (quizzer.Slide, error)
+	ListSlides(ctx context.Context, quizID string) ([]quizzer.Slide, error)
+	UpdateSlide(ctx context.Context, slide quizzer.Slide) error
+	DeleteSlide(ctx context.Context, id string) error
+
+	CreateSection(ctx context.Context, section quizzer.Section) error
+	GetSection(ctx context.Context, id string) (quizzer.Section, error)
+	ListSections(ctx context.Context, quizID string) ([]quizzer.Section, error)
+	UpdateSection(ctx context.Context, section quizzer.Section) error
+	DeleteSection(ctx context.Context, id string) error
+
+	CreateGame(ctx context.Context, game quizzer.Game) error
+	GetGame(ctx context.Context, id string) (quizzer.Game, error)
+	ListGames(ctx context.Context, hostID string) ([]quizzer.Game, error)
+
+	CreateGameParticipant(ctx context.Context, participant quizzer.GameParticipant) error
+	GetGameParticipant(ctx context.Context, gameID, userID string) (quizzer.GameParticipant, error)
+	ListGameParticipants(ctx context.Context, gameID string) ([]quizzer.GameParticipant, error)
+	UpdateGameParticipant(ctx context.Context, participant quizzer.GameParticipant) error
+
+	SaveGameAnswer(ctx context.Context, answer quizzer.GameAnswer) error
+	ListGameAnswers(ctx context.Context, participantIDs ...string) ([]quizzer.GameAnswer, error)
+
+	CreateScheduledGame(ctx context.Context, game quizzer.ScheduledGame) error
+	GetScheduledGame(ctx context.Context, id string) (quizzer.ScheduledGame, error)
+	LockScheduledGame(ctx context.Context, id string) (quizzer.ScheduledGame, error)
+	ListScheduledGames(ctx context.Context, hostID string) ([]quizzer.ScheduledGame, error)
+	ListDueScheduledGames(ctx context.Context, at time.Time) ([]quizzer.ScheduledGame, error)
+	UpdateScheduledGame(ctx context.Context, game quizzer.ScheduledGame) error
+	DeleteScheduledGame(ctx context.Context, id string) error
 }
 
 var _ Session = &session{}
@@ -0,0 +1,107 @@
+package postgres
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/rs/zerolog/log"
+	"github.com/william-joh/quizzer/server/internal/quizzer"
+)
+
+func (s *session) CreateSlide(ctx context.Context, slide quizzer.Slide) error {
+	log.Debug().Str("id", slide.ID).Str("quizID", slide.QuizID).Str("title", slide.Title).Int("index", slide.Index).Msg("creating slide")
+
+	sql, args, err := psql().Insert("slides").
+		Columns("id", "quiz_id", "index", "title", "body", "image_url", "video_url").
+		Values(slide.ID, slide.QuizID, slide.Index, slide.Title, slide.Body, slide.ImageURL, slide.VideoURL).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = s.conn.Exec(ctx, sql, args...)
+	return err
+}
+
+func (s *session) GetSlide(ctx context.Context, id string) (quizzer.Slide, error) {
+	log.Debug().Str("id", id).Msg("getting slide")
+
+	sql, args, err := psql().Select("id", "quiz_id", "index", "title", "body", "image_url", "video_url").
+		From("slides").
+		Where(sq.Eq{"id": id}).ToSql()
+	if err != nil {
+		return quizzer.Slide{}, err
+	}
+
+	row := s.conn.QueryRow(ctx, sql, args...)
+	var slide quizzer.Slide
+	err = row.Scan(&slide.ID, &slide.QuizID, &slide.Index, &slide.Title, &slide.Body, &slide.ImageURL, &slide.VideoURL)
+	if err != nil {
+		return quizzer.Slide{}, err
+	}
+
+	return slide, nil
+}
+
+func (s *session) ListSlides(ctx context.Context, quizID string) ([]quizzer.Slide, error) {
+	log.Debug().Str("quizID", quizID).Msg("listing slides")
+
+	sql, args, err := psql().Select("id", "quiz_id", "index", "title", "body", "image_url", "video_url").
+		From("slides").
+		Where(sq.Eq{"quiz_id": quizID}).
+		OrderBy("index").ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.conn.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	slides := []quizzer.Slide{}
+	for rows.Next() {
+		var slide quizzer.Slide
+		err = rows.Scan(&slide.ID, &slide.QuizID, &slide.Index, &slide.Title, &slide.Body, &slide.ImageURL, &slide.VideoURL)
+		if err != nil {
+			return nil, err
+		}
+		slides = append(slides, slide)
+	}
+
+	return slides, rows.Err()
+}
+
+func (s *session) UpdateSlide(ctx context.Context, slide quizzer.Slide) error {
+	log.Debug().Str("id", slide.ID).Str("title", slide.Title).Int("index", slide.Index).Msg("updating slide")
+
+	sql, args, err := psql().Update("slides").
+		SetMap(map[string]interface{}{
+			"index":     slide.Index,
+			"title":     slide.Title,
+			"body":      slide.Body,
+			"image_url": slide.ImageURL,
+			"video_url": slide.VideoURL,
+		}).
+		Where(sq.Eq{"id": slide.ID}).ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = s.conn.Exec(ctx, sql, args...)
+	return err
+}
+
+func (s *session) DeleteSlide(ctx context.Context, id string) error {
+	log.Debug().Str("id", id).Msg("deleting slide")
+
+	sql, args, err := psql().Delete("slides").
+		Where(sq.Eq{"id": id}).ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = s.conn.Exec(ctx, sql, args...)
+	return err
+}
@@ -0,0 +1,100 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/william-joh/quizzer/server/internal/quizzer"
+)
+
+func TestSlides(t *testing.T) {
+	db := SetupTestDB(t)
+
+	err := db.Do(context.Background()).CreateUser(context.Background(), "testuser-id", "testuser", "testpassword")
+	require.NoError(t, err)
+
+	err = db.Do(context.Background()).CreateQuiz(context.Background(), "testquiz-id", "testquiz", "testuser-id")
+	require.NoError(t, err)
+
+	t.Run("get non-existing slide", func(t *testing.T) {
+		_, err := db.Do(context.Background()).GetSlide(context.Background(), "testslide")
+		require.Error(t, err)
+	})
+
+	t.Run("create slide", func(t *testing.T) {
+		err := db.Do(context.Background()).CreateSlide(context.Background(), quizzer.Slide{
+			ID:       "testslide-id2",
+			QuizID:   "testquiz-id",
+			Index:    3,
+			Title:    "testslide2",
+			Body:     "**halftime**",
+			VideoURL: asPtr("testurl"),
+		})
+		require.NoError(t, err)
+
+		err = db.Do(context.Background()).CreateSlide(context.Background(), quizzer.Slide{
+			ID:       "testslide-id1",
+			QuizID:   "testquiz-id",
+			Index:    0,
+			Title:    "testslide1",
+			Body:     "# welcome",
+			ImageURL: asPtr("testimage"),
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("list slides", func(t *testing.T) {
+		slides, err := db.Do(context.Background()).ListSlides(context.Background(), "testquiz-id")
+		require.NoError(t, err)
+		require.Equal(t, []quizzer.Slide{
+			{
+				ID:       "testslide-id1",
+				QuizID:   "testquiz-id",
+				Index:    0,
+				Title:    "testslide1",
+				Body:     "# welcome",
+				ImageURL: asPtr("testimage"),
+			},
+			{
+				ID:       "testslide-id2",
+				QuizID:   "testquiz-id",
+				Index:    3,
+				Title:    "testslide2",
+				Body:     "**halftime**",
+				VideoURL: asPtr("testurl"),
+			},
+		}, slides)
+	})
+
+	t.Run("edit slide", func(t *testing.T) {
+		err := db.Do(context.Background()).UpdateSlide(context.Background(), quizzer.Slide{
+			ID:     "testslide-id1",
+			QuizID: "testquiz-id",
+			Index:  1,
+			Title:  "editedslide1",
+			Body:   "edited",
+		})
+		require.NoError(t, err)
+
+		slide, err := db.Do(context.Background()).GetSlide(context.Background(), "testslide-id1")
+		require.NoError(t, err)
+		require.Equal(t, quizzer.Slide{
+			ID:     "testslide-id1",
+			QuizID: "testquiz-id",
+			Index:  1,
+			Title:  "editedslide1",
+			Body:   "edited",
+		}, slide)
+	})
+
+	t.Run("delete slide", func(t *testing.T) {
+		err := db.Do(context.Background()).DeleteSlide(context.Background(), "testslide-id2")
+		require.NoError(t, err)
+
+		slides, err := db.Do(context.Background()).ListSlides(context.Background(), "testquiz-id")
+		require.NoError(t, err)
+		require.Len(t, slides, 1)
+		require.Equal(t, "testslide-id1", slides[0].ID)
+	})
+}
@@ -0,0 +1,64 @@
+package quizzer
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type GameMode string
+
+const (
+	// GameModeLive is a game where everyone answers at the same time, led by
+	// the host.
+	GameModeLive GameMode = "live"
+	// GameModeAssignment is a game where participants answer at their own
+	// pace while it is open.
+	GameModeAssignment GameMode = "assignment"
+)
+
+// Game is a finished live game or an assignment, kept so that hosts can review
+// the results.
+type Game struct {
+	ID       string          `json:"id"`
+	QuizID   string          `json:"quizId"`
+	HostID   string          `json:"hostId"`
+	Mode     GameMode        `json:"mode"`
+	Settings json.RawMessage `json:"settings"`
+	// OpensAt is when a live game started, or when an assignment opens.
+	OpensAt time.Time `json:"opensAt"`
+	// ClosesAt is when a live game ended, or when an assignment closes.
+	ClosesAt time.Time `json:"closesAt"`
+}
+
+// IsOpen reports whether participants may answer at the given time.
+func (g Game) IsOpen(at time.Time) bool {
+	return !at.Before(g.OpensAt) && at.Before(g.ClosesAt)
+}
+
+type GameParticipant struct {
+	ID     string `json:"id"`
+	GameID string `json:"gameId"`
+	// UserID is the ID of the user, or of the guest if IsGuest is set.
+	UserID     string     `json:"userId"`
+	Name       string     `json:"name"`
+	Avatar     string     `json:"avatar,omitempty"`
+	Color      string     `json:"color,omitempty"`
+	IsGuest    bool       `json:"isGuest"`
+	Score      int        `json:"score"`
+	StartedAt  time.Time  `json:"startedAt"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+}
+
+type GameAnswer struct {
+	ParticipantID string `json:"participantId"`
+	QuestionID    string `json:"questionId"`
+	// OptionID is the chosen answer option, or nil if the question was not
+	// answered in time.
+	OptionID  *string `json:"optionId"`
+	IsCorrect bool    `json:"isCorrect"`
+	Score     int     `json:"score"`
+	// ShownAt is when the question was shown to the participant, which starts
+	// their timer in assignments.
+	ShownAt    *time.Time `json:"shownAt,omitempty"`
+	AnsweredAt *time.Time `json:"answeredAt,omitempty"`
+}
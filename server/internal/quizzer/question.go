@@ -1,14 +1,77 @@
 package quizzer
 
+import "errors"
+
 type Question struct {
-	ID                    string   `json:"id"`
-	QuizID                string   `json:"quizId"`
-	Question              string   `json:"question"`
-	Index                 int      `json:"index"`
-	TimeLimitSeconds      uint64   `json:"timeLimitSeconds"`
-	Answers               []string `json:"answers"`
-	CorrectAnswers        []string `json:"correctAnswers"`
-	VideoURL              *string  `json:"videoUrl,omitempty"`
-	VideoStartTimeSeconds *uint64  `json:"videoStartTimeSeconds,omitempty"`
-	VideoEndTimeSeconds   *uint64  `json:"videoEndTimeSeconds,omitempty"`
+	ID                    string         `json:"id"`
+	QuizID                string         `json:"quizId"`
+	SectionID             *string        `json:"sectionId,omitempty"`
+	Question              string         `json:"question"`
+	Index                 int            `json:"index"`
+	TimeLimitSeconds      uint64         `json:"timeLimitSeconds"`
+	Options               []AnswerOption `json:"options"`
+	VideoURL              *string        `json:"videoUrl,omitempty"`
+	VideoStartTimeSeconds *uint64        `json:"videoStartTimeSeconds,omitempty"`
+	VideoEndTimeSeconds   *uint64        `json:"videoEndTimeSeconds,omitempty"`
+	// ImageURL and AudioURL point to media uploaded for the question.
+	ImageURL *string `json:"imageUrl,omitempty"`
+	AudioURL *string `json:"audioUrl,omitempty"`
+}
+
+type AnswerOption struct {
+	ID         string  `json:"id"`
+	QuestionID string  `json:"questionId"`
+	Index      int     `json:"index"`
+	Text       string  `json:"text"`
+	ImageURL   *string `json:"imageUrl,omitempty"`
+	AudioURL   *string `json:"audioUrl,omitempty"`
+	IsCorrect  bool    `json:"isCorrect"`
+}
+
+// Validate checks that the question can be answered, and that its video can
+// be played.
+func (q Question) Validate() error {
+	if err := q.validateVideo(); err != nil {
+		return err
+	}
+
+	if len(q.Options) < 2 {
+		return errors.New("at least two answer options are required")
+	}
+
+	for _, o := range q.Options {
+		if o.IsCorrect {
+			return nil
+		}
+	}
+	return errors.New("at least one answer option must be correct")
+}
+
+// Option returns the answer option with the given ID.
+func (q Question) Option(id string) (AnswerOption, bool) {
+	for _, o := range q.Options {
+		if o.ID == id {
+			return o, true
+		}
+	}
+	return AnswerOption{}, false
+}
+
+// IsCorrect reports whether the answer option with the given ID is a correct
+// answer to the question.
+func (q Question) IsCorrect(optionID string) bool {
+	o, ok := q.Option(optionID)
+	return ok && o.IsCorrect
+}
+
+// CorrectOptions returns the answer options that are correct answers to the
+// question.
+func (q Question) CorrectOptions() []AnswerOption {
+	options := []AnswerOption{}
+	for _, o := range q.Options {
+		if o.IsCorrect {
+			options = append(options, o)
+		}
+	}
+	return options
 }
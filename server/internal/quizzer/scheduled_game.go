@@ -0,0 +1,31 @@
+package quizzer
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type ScheduledGameStatus string
+
+const (
+	// ScheduledGameStatusPending is a scheduled game that has not opened yet.
+	ScheduledGameStatusPending ScheduledGameStatus = "pending"
+	// ScheduledGameStatusOpened is a scheduled game whose lobby has been
+	// opened.
+	ScheduledGameStatusOpened ScheduledGameStatus = "opened"
+	// ScheduledGameStatusMissed is a scheduled game that could not be opened
+	// in time, e.g. because the server was down.
+	ScheduledGameStatusMissed ScheduledGameStatus = "missed"
+)
+
+// ScheduledGame is a live game that opens its lobby on its own at StartAt.
+type ScheduledGame struct {
+	ID       string              `json:"id"`
+	QuizID   string              `json:"quizId"`
+	HostID   string              `json:"hostId"`
+	StartAt  time.Time           `json:"startAt"`
+	Settings json.RawMessage     `json:"settings"`
+	Status   ScheduledGameStatus `json:"status"`
+	// Code is the code of the game once it has been opened.
+	Code *string `json:"code,omitempty"`
+}
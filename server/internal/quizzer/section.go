@@ -0,0 +1,84 @@
+package quizzer
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Section is a round of a quiz. The questions of a section are played one
+// after another, ordered by their index.
+type Section struct {
+	ID     string `json:"id"`
+	QuizID string `json:"quizId"`
+	Index  int    `json:"index"`
+	Title  string `json:"title"`
+	// Multiplier multiplies the points given for the questions of the
+	// section. It is optional, zero means the points are not multiplied.
+	Multiplier int `json:"multiplier,omitempty"`
+}
+
+// Validate checks that the section can be played.
+func (s Section) Validate() error {
+	if strings.TrimSpace(s.Title) == "" {
+		return errors.New("section title cannot be empty")
+	}
+	if s.Multiplier < 0 {
+		return errors.New("section multiplier cannot be negative")
+	}
+	return nil
+}
+
+// Factor returns what the points for the questions of the section are
+// multiplied by.
+func (s Section) Factor() int {
+	if s.Multiplier == 0 {
+		return 1
+	}
+	return s.Multiplier
+}
+
+// ValidateSections checks that every question belongs to one of the sections,
+// and that the questions of a section are not interleaved with those of
+// another, so that the sections are played in order. Quizzes without sections
+// are always valid.
+func ValidateSections(sections []Section, questions []Question) error {
+	if len(sections) == 0 {
+		return nil
+	}
+
+	order := map[string]int{}
+	indexes := map[int]bool{}
+	for _, s := range sections {
+		if _, ok := order[s.ID]; ok {
+			return fmt.Errorf("duplicate section %s", s.ID)
+		}
+		if indexes[s.Index] {
+			return fmt.Errorf("section index %d is already used", s.Index)
+		}
+		order[s.ID] = s.Index
+		indexes[s.Index] = true
+	}
+
+	questions = slices.Clone(questions)
+	slices.SortStableFunc(questions, func(a, b Question) int { return cmp.Compare(a.Index, b.Index) })
+
+	last := 0
+	for i, q := range questions {
+		if q.SectionID == nil {
+			return fmt.Errorf("question %s is not in a section", q.Question)
+		}
+		index, ok := order[*q.SectionID]
+		if !ok {
+			return fmt.Errorf("question %s is in unknown section %s", q.Question, *q.SectionID)
+		}
+		if i > 0 && index < last {
+			return fmt.Errorf("question %s is out of order with its section", q.Question)
+		}
+		last = index
+	}
+
+	return nil
+}
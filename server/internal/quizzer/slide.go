@@ -0,0 +1,36 @@
+package quizzer
+
+import (
+	"errors"
+	"strings"
+)
+
+// Slide is an information item shown between the questions of a quiz, e.g. to
+// introduce a round. Slides share their ordering with the questions of the
+// quiz, so an index is never used by both a slide and a question.
+type Slide struct {
+	ID     string `json:"id"`
+	QuizID string `json:"quizId"`
+	Index  int    `json:"index"`
+	Title  string `json:"title"`
+	// Body is markdown.
+	Body     string  `json:"body"`
+	ImageURL *string `json:"imageUrl,omitempty"`
+	VideoURL *string `json:"videoUrl,omitempty"`
+}
+
+// Validate checks that the slide can be shown.
+func (s Slide) Validate() error {
+	if strings.TrimSpace(s.Title) == "" {
+		return errors.New("slide title cannot be empty")
+	}
+	if s.ImageURL != nil && s.VideoURL != nil {
+		return errors.New("a slide can have an image or a video, not both")
+	}
+	if s.VideoURL != nil {
+		if _, err := ParseVideoURL(*s.VideoURL); err != nil {
+			return err
+		}
+	}
+	return nil
+}
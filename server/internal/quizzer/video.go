@@ -0,0 +1,102 @@
+package quizzer
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// VideoProvider is a site videos of questions can be played from.
+type VideoProvider string
+
+const (
+	VideoProviderYouTube VideoProvider = "youtube"
+	VideoProviderVimeo   VideoProvider = "vimeo"
+)
+
+var (
+	youTubeID = regexp.MustCompile(`^[A-Za-z0-9_-]{11}$`)
+	vimeoID   = regexp.MustCompile(`^[0-9]+$`)
+)
+
+// Video is a video on one of the supported providers.
+type Video struct {
+	Provider VideoProvider `json:"provider"`
+	ID       string        `json:"id"`
+}
+
+// ParseVideoURL returns the video the URL points to. Only the URL itself is
+// looked at, the video is not checked to exist.
+func ParseVideoURL(raw string) (Video, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return Video{}, fmt.Errorf("invalid video URL: %w", err)
+	}
+	if u.Scheme != "https" && u.Scheme != "http" {
+		return Video{}, errors.New("video URL must be http or https")
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	path := strings.Split(strings.Trim(u.Path, "/"), "/")
+
+	var video Video
+	switch host {
+	case "youtube.com", "m.youtube.com":
+		video.Provider = VideoProviderYouTube
+		switch {
+		case len(path) == 1 && path[0] == "watch":
+			video.ID = u.Query().Get("v")
+		case len(path) == 2 && (path[0] == "embed" || path[0] == "shorts"):
+			video.ID = path[1]
+		}
+	case "youtu.be":
+		video.Provider = VideoProviderYouTube
+		if len(path) == 1 {
+			video.ID = path[0]
+		}
+	case "vimeo.com":
+		video.Provider = VideoProviderVimeo
+		if len(path) == 1 {
+			video.ID = path[0]
+		}
+	case "player.vimeo.com":
+		video.Provider = VideoProviderVimeo
+		if len(path) == 2 && path[0] == "video" {
+			video.ID = path[1]
+		}
+	default:
+		return Video{}, fmt.Errorf("unsupported video provider: %s", host)
+	}
+
+	valid := youTubeID
+	if video.Provider == VideoProviderVimeo {
+		valid = vimeoID
+	}
+	if !valid.MatchString(video.ID) {
+		return Video{}, fmt.Errorf("invalid %s video URL", video.Provider)
+	}
+
+	return video, nil
+}
+
+// validateVideo checks that the video of the question can be played, and that
+// the clip to play is not empty.
+func (q Question) validateVideo() error {
+	if q.VideoURL == nil {
+		if q.VideoStartTimeSeconds != nil || q.VideoEndTimeSeconds != nil {
+			return errors.New("video times require a video URL")
+		}
+		return nil
+	}
+
+	if _, err := ParseVideoURL(*q.VideoURL); err != nil {
+		return err
+	}
+
+	if q.VideoStartTimeSeconds != nil && q.VideoEndTimeSeconds != nil && *q.VideoStartTimeSeconds >= *q.VideoEndTimeSeconds {
+		return errors.New("video start time must be before its end time")
+	}
+	return nil
+}
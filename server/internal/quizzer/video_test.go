@@ -0,0 +1,46 @@
+package quizzer_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/william-joh/quizzer/server/internal/quizzer"
+)
+
+func TestParseVideoURL(t *testing.T) {
+	tests := []struct {
+		url   string
+		video quizzer.Video
+		valid bool
+	}{
+		{url: "https://www.youtube.com/watch?v=dQw4w9WgXcQ", video: quizzer.Video{Provider: quizzer.VideoProviderYouTube, ID: "dQw4w9WgXcQ"}, valid: true},
+		{url: "https://m.youtube.com/watch?v=dQw4w9WgXcQ&t=42", video: quizzer.Video{Provider: quizzer.VideoProviderYouTube, ID: "dQw4w9WgXcQ"}, valid: true},
+		{url: "http://YouTube.com/embed/dQw4w9WgXcQ", video: quizzer.Video{Provider: quizzer.VideoProviderYouTube, ID: "dQw4w9WgXcQ"}, valid: true},
+		{url: "https://youtube.com/shorts/dQw4w9WgXcQ/", video: quizzer.Video{Provider: quizzer.VideoProviderYouTube, ID: "dQw4w9WgXcQ"}, valid: true},
+		{url: "https://youtu.be/dQw4w9WgXcQ", video: quizzer.Video{Provider: quizzer.VideoProviderYouTube, ID: "dQw4w9WgXcQ"}, valid: true},
+		{url: "https://vimeo.com/76979871", video: quizzer.Video{Provider: quizzer.VideoProviderVimeo, ID: "76979871"}, valid: true},
+		{url: "https://player.vimeo.com/video/76979871", video: quizzer.Video{Provider: quizzer.VideoProviderVimeo, ID: "76979871"}, valid: true},
+		{url: "https://www.youtube.com/watch?v=short"},
+		{url: "https://www.youtube.com/watch"},
+		{url: "https://www.youtube.com/channel/dQw4w9WgXcQ"},
+		{url: "https://youtu.be/"},
+		{url: "https://vimeo.com/channels/staffpicks"},
+		{url: "https://player.vimeo.com/76979871"},
+		{url: "ftp://youtu.be/dQw4w9WgXcQ"},
+		{url: "javascript:alert(1)"},
+		{url: "https://example.com/watch?v=dQw4w9WgXcQ"},
+		{url: "https://youtube.com.example.com/watch?v=dQw4w9WgXcQ"},
+		{url: "://youtu.be"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			video, err := quizzer.ParseVideoURL(tt.url)
+			if !tt.valid {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.video, video)
+		})
+	}
+}
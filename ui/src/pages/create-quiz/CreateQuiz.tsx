@@ -355,8 +355,7 @@ async function doCreateQuiz(data: FormData) {
       return {
         question: q.question,
         index: i,
-        answers: q.answers.map((a) => a.text),
-        correctAnswers: q.answers.filter((a) => a.isCorrect).map((a) => a.text),
+        options: q.answers.map((a) => ({ text: a.text, isCorrect: a.isCorrect })),
         timeLimitSeconds: q.timeLimitSeconds,
       };
     }),
@@ -6,6 +6,13 @@ import { AlertCircle } from "lucide-react";
 import { HostGame } from "./host/HostGame";
 import { ParticipantGame } from "./participant/ParticipantGame";
 
+export interface AnswerOption {
+  id: string;
+  text: string;
+  imageUrl?: string;
+  audioUrl?: string;
+}
+
 export interface QuizInfo {
   title: string;
   hostName: string;
@@ -13,6 +20,25 @@ export interface QuizInfo {
   participants: string[];
 }
 
+// answerPing echoes pings from the server, which measures our latency to
+// compensate answer times. It returns whether the message was a ping.
+export function answerPing(
+  ws: WebSocket,
+  data: { type?: string; nonce?: string }
+) {
+  if (data.type != "ping") {
+    return false;
+  }
+
+  ws.send(
+    JSON.stringify({
+      type: "Pong",
+      data: { nonce: data.nonce, clientTime: Date.now() },
+    })
+  );
+  return true;
+}
+
 export function Game({ participant }: { participant: Participant }) {
   console.log("Participant", participant);
   const navigate = useNavigate();
@@ -22,22 +48,32 @@ export function Game({ participant }: { participant: Participant }) {
   const [connectionError, setConnectionError] = useState(false);
 
   const ws = useRef<WebSocket>(
-    new WebSocket(`ws://127.0.0.1:8000/game/${code}`)
+    new WebSocket(
+      participant.guestToken
+        ? `ws://127.0.0.1:8000/game/${code}?guestToken=${encodeURIComponent(participant.guestToken)}`
+        : `ws://127.0.0.1:8000/game/${code}`
+    )
   );
 
   // Create websocket to connect to the game
   useEffect(() => {
     console.log("Code", code);
     ws.current.onopen = () => {
-      ws.current.send(
-        `{ "type": "Join", "data": { "username": "${participant.username}", "id": "${participant.id}"} }`
-      );
+      ws.current.send(`{ "type": "Join" }`);
     };
     ws.current.onmessage = (event) => {
       console.log("Got message", event.data);
       const data = JSON.parse(event.data);
       console.log("Data", data);
 
+      if (answerPing(ws.current, data)) {
+        return;
+      }
+      // Other events before the game is shown are not needed, only the state.
+      if (data.type) {
+        return;
+      }
+
       if (data.phase == "lobby") {
         setQuizInfo({
           title: data.quizTitle,
@@ -99,11 +135,7 @@ export function Game({ participant }: { participant: Participant }) {
       )}
 
       {!quizInfo.isHost && (
-        <ParticipantGame
-          ws={ws.current}
-          initialQuizInfo={quizInfo}
-          participant={participant}
-        />
+        <ParticipantGame ws={ws.current} initialQuizInfo={quizInfo} />
       )}
     </div>
   );
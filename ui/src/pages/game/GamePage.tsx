@@ -4,10 +4,12 @@ import { Game } from "./Game";
 import { Button } from "@/components/ui/button";
 import { Input } from "@/components/ui/input";
 import { Card, CardContent, CardHeader, CardTitle } from "@/components/ui/card";
+import { request } from "@/lib/axios";
 
 export interface Participant {
   username: string;
   id: string;
+  guestToken?: string;
 }
 
 export function GamePage() {
@@ -19,12 +21,14 @@ export function GamePage() {
   if (!currentParticipant) {
     return (
       <UserNameForm
-        onSubmit={(username) =>
+        onSubmit={async (username) => {
+          const guest = await createGuest(username);
           setCurrentParticipant({
-            username,
-            id: crypto.randomUUID(),
-          })
-        }
+            username: guest.name,
+            id: guest.id,
+            guestToken: guest.token,
+          });
+        }}
       />
     );
   }
@@ -62,6 +66,17 @@ function UserNameForm({ onSubmit }: { onSubmit: (username: string) => void }) {
   );
 }
 
+async function createGuest(
+  name: string
+): Promise<{ id: string; name: string; token: string }> {
+  const response = await request({
+    url: "/guests",
+    method: "POST",
+    data: { name },
+  });
+  return response.data;
+}
+
 function getCurrentParticipant(currentUser: User | null): Participant | null {
   if (!currentUser) {
     return null;
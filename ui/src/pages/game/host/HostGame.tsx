@@ -1,9 +1,10 @@
 import { useEffect, useState } from "react";
-import { QuizInfo } from "../Game";
+import { AnswerOption, QuizInfo } from "../Game";
 import { GameInfo } from "../GameInfo";
 import { HostLobby } from "./HostLobby";
 import { HostQuestionPhase } from "./HostQuestionPhase";
 import { HostResultsPhase } from "./HostResultsPhase";
+import { HostAction, HostWaitingPhase } from "./HostWaitingPhase";
 
 interface HostGameProps {
   ws: WebSocket;
@@ -12,7 +13,7 @@ interface HostGameProps {
 
 export interface HostQuestion {
   question: string;
-  options: string[];
+  options: AnswerOption[];
   timeLimit: number;
 }
 
@@ -22,16 +23,62 @@ export interface HostResults {
   results: { name: string; nrCorrect: number }[];
 }
 
+interface Waiting {
+  title: string;
+  description?: string;
+}
+
+// waitingFor returns what is shown in the phases between questions.
+function waitingFor(data: {
+  phase: string;
+  question?: string;
+  title?: string;
+  body?: string;
+  section?: string;
+  round?: number;
+  totalRounds?: number;
+}): Waiting {
+  switch (data.phase) {
+    case "countdown":
+      return { title: "Get ready", description: data.question };
+    case "media":
+      return { title: "Playing media", description: data.question };
+    case "paused":
+      return { title: "Paused", description: data.question };
+    case "reveal":
+      return { title: "Answer revealed", description: data.question };
+    case "slide":
+      return { title: data.title || "", description: data.body };
+    case "intermission":
+      return {
+        title: `End of round ${data.round} of ${data.totalRounds}`,
+        description: data.section,
+      };
+    case "finished":
+      return { title: "The quiz has finished" };
+    default:
+      return { title: "Waiting" };
+  }
+}
+
 export function HostGame({ ws, initialQuizInfo }: HostGameProps) {
   const [quizInfo, setQuizInfo] = useState(initialQuizInfo);
   const [phase, setPhase] = useState("lobby");
   const [question, setQuestion] = useState<HostQuestion | null>(null);
   const [results, setResults] = useState<HostResults | null>(null);
+  const [waiting, setWaiting] = useState<Waiting>({ title: "Waiting" });
 
   useEffect(() => {
     ws.onmessage = (event) => {
       const data = JSON.parse(event.data);
       console.log("raw data", data);
+
+      // Events such as errors, reactions and chat messages are not quiz
+      // states.
+      if (data.type) {
+        return;
+      }
+
       setPhase(data.phase);
 
       switch (data.phase) {
@@ -60,7 +107,7 @@ export function HostGame({ ws, initialQuizInfo }: HostGameProps) {
           break;
 
         default:
-          throw new Error("Unknown phase" + data);
+          setWaiting(waitingFor(data));
       }
     };
 
@@ -85,6 +132,20 @@ export function HostGame({ ws, initialQuizInfo }: HostGameProps) {
     ws.send(`{ "type": "NextQuestion" }`);
   };
 
+  const send = (type: string) => () => ws.send(JSON.stringify({ type }));
+
+  const actions: Record<string, HostAction[]> = {
+    countdown: [{ label: "Skip", onClick: send("NextQuestion") }],
+    media: [
+      { label: "Media Ended", onClick: send("MediaEnded") },
+      { label: "Skip", onClick: send("NextQuestion") },
+    ],
+    paused: [{ label: "Resume", onClick: send("ResumeQuestion") }],
+    reveal: [{ label: "Show Results", onClick: send("NextQuestion") }],
+    slide: [{ label: "Continue", onClick: send("NextQuestion") }],
+    intermission: [{ label: "Continue", onClick: send("NextQuestion") }],
+  };
+
   if (phase == "question" && !question)
     throw new Error("No question when in question phase");
   if (phase == "results" && !results)
@@ -108,6 +169,10 @@ export function HostGame({ ws, initialQuizInfo }: HostGameProps) {
       {phase == "results" && results && (
         <HostResultsPhase {...results} onContinue={nextQuestion} />
       )}
+
+      {phase != "lobby" && phase != "question" && phase != "results" && (
+        <HostWaitingPhase {...waiting} actions={actions[phase] || []} />
+      )}
     </div>
   );
 }
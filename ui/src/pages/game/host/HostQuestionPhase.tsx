@@ -48,7 +48,7 @@ export function HostQuestionPhase({
               key={index}
               className="p-6 rounded-lg bg-secondary/50 text-lg font-medium"
             >
-              {option}
+              {option.text}
             </div>
           ))}
         </div>
@@ -0,0 +1,39 @@
+import { Button } from "@/components/ui/button";
+import { Card, CardContent, CardHeader } from "@/components/ui/card";
+
+export interface HostAction {
+  label: string;
+  onClick: () => void;
+}
+
+// HostWaitingPhase shows the phases between questions, with the actions the
+// host can take to move on.
+export function HostWaitingPhase({
+  title,
+  description,
+  actions,
+}: {
+  title: string;
+  description?: string;
+  actions: HostAction[];
+}) {
+  return (
+    <Card className="mt-4">
+      <CardHeader className="flex flex-row items-center justify-between pb-2">
+        <h2 className="text-2xl font-semibold tracking-tight">{title}</h2>
+        <div className="flex gap-2">
+          {actions.map((action) => (
+            <Button key={action.label} onClick={action.onClick} size="lg">
+              {action.label}
+            </Button>
+          ))}
+        </div>
+      </CardHeader>
+      {description && (
+        <CardContent className="py-6">
+          <p className="text-lg whitespace-pre-wrap">{description}</p>
+        </CardContent>
+      )}
+    </Card>
+  );
+}
@@ -1,29 +1,53 @@
-import { useEffect, useState } from "react";
-import { QuizInfo } from "../Game";
+import { useEffect, useRef, useState } from "react";
+import { AnswerOption, QuizInfo, answerPing } from "../Game";
 import { GameInfo } from "../GameInfo";
 import { ParticipantQuestionPhase } from "./ParticipantQuestionPhase";
-import { Participant } from "../GamePage";
 import { ParticipantResultsPhase } from "./ParticipantResultsPhase";
 import { ParticipantLobby } from "./ParticipantLobby";
+import { ParticipantWaitingPhase } from "./ParticipantWaitingPhase";
+
+// waitingTitles are shown in the phases in which there is nothing to answer.
+const waitingTitles: Record<string, string> = {
+  countdown: "Get ready",
+  media: "Watch the host's screen",
+  paused: "Paused",
+  reveal: "Answer revealed",
+  intermission: "End of the round",
+  finished: "The quiz has finished",
+};
 
 interface ParticipantGameProps {
   ws: WebSocket;
   initialQuizInfo: QuizInfo;
-  participant: Participant;
 }
 
 export function ParticipantGame({
   ws,
   initialQuizInfo,
-  participant,
 }: ParticipantGameProps) {
   const [quizInfo, setQuizInfo] = useState(initialQuizInfo);
   const [phase, setPhase] = useState("lobby");
-  const [options, setOptions] = useState<string[]>([]);
+  const [options, setOptions] = useState<AnswerOption[]>([]);
+  const [waiting, setWaiting] = useState<{
+    title: string;
+    description?: string;
+  }>({ title: "Waiting" });
+  const questionStartedAt = useRef(0);
+  const questionReceivedAt = useRef(0);
 
   useEffect(() => {
     ws.onmessage = (event) => {
       const data = JSON.parse(event.data);
+
+      if (answerPing(ws, data)) {
+        return;
+      }
+      // Events such as errors, reactions and chat messages are not quiz
+      // states.
+      if (data.type) {
+        return;
+      }
+
       setPhase(data.phase);
 
       switch (data.phase) {
@@ -36,12 +60,24 @@ export function ParticipantGame({
           });
           break;
         case "question":
+          // The state is sent again whenever someone answers, only the
+          // first time a question is received counts.
+          if (data.startedAt != questionStartedAt.current) {
+            questionStartedAt.current = data.startedAt;
+            questionReceivedAt.current = performance.now();
+          }
           setOptions(data.options);
           break;
         case "results":
           break;
+        case "slide":
+          setWaiting({ title: data.title, description: data.body });
+          break;
         default:
-          throw new Error("Unknown phase");
+          setWaiting({
+            title: waitingTitles[data.phase] || "Waiting",
+            description: data.question,
+          });
       }
     };
 
@@ -50,11 +86,14 @@ export function ParticipantGame({
     // };
   }, [ws]);
 
-  const answerQuestion = (answer: string) => {
+  const answerQuestion = (optionId: string) => {
     ws.send(
       JSON.stringify({
         type: "AnswerQuestion",
-        data: { answer, id: participant.id },
+        data: {
+          optionId,
+          elapsedMs: performance.now() - questionReceivedAt.current,
+        },
       })
     );
   };
@@ -73,6 +112,10 @@ export function ParticipantGame({
       )}
 
       {phase == "results" && <ParticipantResultsPhase />}
+
+      {phase != "lobby" && phase != "question" && phase != "results" && (
+        <ParticipantWaitingPhase {...waiting} />
+      )}
     </div>
   );
 }
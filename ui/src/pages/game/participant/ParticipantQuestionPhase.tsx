@@ -1,13 +1,14 @@
 import { Button } from "@/components/ui/button";
 import { Card, CardContent, CardHeader } from "@/components/ui/card";
 import { useState } from "react";
+import { AnswerOption } from "../Game";
 
 export function ParticipantQuestionPhase({
   options,
   onSelectQuestion,
 }: {
-  options: string[];
-  onSelectQuestion: (option: string) => void;
+  options: AnswerOption[];
+  onSelectQuestion: (optionId: string) => void;
 }) {
   const [selectedOption, setSelectedOption] = useState<string | null>(null);
 
@@ -28,17 +29,17 @@ export function ParticipantQuestionPhase({
       </CardHeader>
       <CardContent>
         <div className="grid grid-cols-1 md:grid-cols-2 gap-4">
-          {options.map((option, index) => (
+          {options.map((option) => (
             <Button
-              key={index}
-              onClick={() => handleOptionSelect(option)}
-              variant={selectedOption === option ? "default" : "outline"}
+              key={option.id}
+              onClick={() => handleOptionSelect(option.id)}
+              variant={selectedOption === option.id ? "default" : "outline"}
               className={`p-8 h-auto text-lg font-medium transition-all ${
-                selectedOption === option ? "ring-2 ring-primary" : ""
+                selectedOption === option.id ? "ring-2 ring-primary" : ""
               }`}
               disabled={selectedOption !== null}
             >
-              {option}
+              {option.text}
             </Button>
           ))}
         </div>
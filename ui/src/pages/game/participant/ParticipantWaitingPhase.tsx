@@ -0,0 +1,24 @@
+import { Card } from "@/components/ui/card";
+
+// ParticipantWaitingPhase shows the phases in which participants have nothing
+// to answer.
+export function ParticipantWaitingPhase({
+  title,
+  description,
+}: {
+  title: string;
+  description?: string;
+}) {
+  return (
+    <div className="max-w-4xl mx-auto mt-8">
+      <Card className="p-8 text-center">
+        <h2 className="text-2xl font-semibold mb-4">{title}</h2>
+        {description && (
+          <p className="text-lg text-muted-foreground whitespace-pre-wrap">
+            {description}
+          </p>
+        )}
+      </Card>
+    </div>
+  );
+}